@@ -2,6 +2,7 @@ package tinysl
 
 import (
 	"context"
+	"reflect"
 	"slices"
 )
 
@@ -45,7 +46,7 @@ func (node *cleanupNode) updateCleanupNode(id int32, fn Cleanup) {
 
 type cleanupNodeRecord struct {
 	*cleanupNode
-	typeName     string
+	serviceType  reflect.Type
 	dependencies []int32
 }
 
@@ -58,10 +59,11 @@ func buildCleanupNodes(records []*locatorRecord) *cleanupNode {
 	}
 
 	if hasNoDeps {
-		return &cleanupNode{fn: func() {}}
+		return &cleanupNode{fn: func() {}, id: -1}
 	}
 
-	headNode := &cleanupNode{fn: func() {}}
+	// head node must not match any record id
+	headNode := &cleanupNode{fn: func() {}, id: -1}
 
 	nodes := make([]*cleanupNodeRecord, 0)
 	for _, rec := range records {
@@ -104,16 +106,21 @@ func buildCleanupNodeRecord(rec *locatorRecord, records []*locatorRecord) *clean
 	}
 
 	deps := make([]int32, 0)
-	for _, depRecord := range rec.dependencies {
+	for i, depRecord := range rec.dependencies {
+		// lazy dependency can be built after its dependant
+		if _, ok := rec.lazy[i]; ok {
+			continue
+		}
+
 		for _, dep := range records {
-			if rec.constructorType == withErrorAndCleanUp && dep.id == depRecord.id && dep.lifetime == rec.lifetime {
+			if (rec.constructorType == withErrorAndCleanUp || rec.resultField) && dep == depRecord {
 				deps = append(deps, depRecord.id)
 			}
 		}
 	}
 
 	nodeRec := &cleanupNodeRecord{
-		typeName:     rec.typeName,
+		serviceType:  rec.serviceType,
 		dependencies: deps,
 		cleanupNode:  node,
 	}
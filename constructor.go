@@ -1,13 +1,27 @@
 package tinysl
 
-import "reflect"
+import (
+	"reflect"
+	"slices"
+	"strings"
+)
 
 type propertyFiller struct {
-	Type         reflect.Type
-	NewInstance  func(values ...any) (any, error)
-	Dependencies []string
+	Type reflect.Type
+	// Type of instance returned by NewInstance, used to bind service to additional interfaces.
+	Implementation reflect.Type
+	NewInstance    func(values ...any) (any, error)
+	Dependencies   []reflect.Type
+	// Indexes of dependencies of fields tagged with `tinysl:"optional"`.
+	Optional map[int]bool
+	// Names of dependencies of fields tagged with `tinysl:"name=..."` or `tinysl:"group=..."` by their index.
+	Names map[int]string
 }
 
+// In embedded in struct makes it a parameter object.
+// Constructor taking parameter object gets each of its public fields resolved as a dependency.
+type In struct{}
+
 // Type constructor that would automatically fill public fields using registered constructors.
 func T[Type any]() (propertyFiller, error) {
 	t := reflect.TypeOf(new(Type)).Elem()
@@ -16,24 +30,18 @@ func T[Type any]() (propertyFiller, error) {
 		return propertyFiller{}, &TError{T: t}
 	}
 
-	filedIndex := 0
-	fields := make(map[int]int)
-	dependencies := make([]string, 0, 1)
-
-	for i := 0; i < t.NumField(); i++ {
-		if !t.Field(i).IsExported() {
-			continue
-		}
-
-		dependencies = append(dependencies, t.Field(i).Type.String())
-		fields[filedIndex] = i
-		filedIndex++
+	fields, err := publicFields(t)
+	if err != nil {
+		return propertyFiller{}, &TError{T: t, cause: err}
 	}
 
 	return propertyFiller{
-		Type:         reflect.TypeOf(new(Type)).Elem(),
-		Dependencies: dependencies,
-		NewInstance:  getValueInstance[Type](fields),
+		Type:           reflect.TypeOf(new(Type)).Elem(),
+		Implementation: t,
+		Dependencies:   fields.dependencies,
+		Optional:       fields.optional,
+		Names:          fields.names,
+		NewInstance:    getValueInstance[Type](fields.indexes),
 	}, nil
 }
 
@@ -45,29 +53,29 @@ func P[Type any]() (propertyFiller, error) {
 		return propertyFiller{}, &PError{T: t}
 	}
 
-	filedIndex := 0
-	fields := make(map[int]int)
-	dependencies := make([]string, 0, 1)
-
-	for i := 0; i < t.NumField(); i++ {
-		if !t.Field(i).IsExported() {
-			continue
-		}
-
-		dependencies = append(dependencies, t.Field(i).Type.String())
-		fields[filedIndex] = i
-		filedIndex++
+	fields, err := publicFields(t)
+	if err != nil {
+		return propertyFiller{}, &PError{T: t, cause: err}
 	}
 
 	return propertyFiller{
-		Type:         reflect.TypeOf(new(Type)),
-		Dependencies: dependencies,
-		NewInstance:  getPointerInstance[Type](fields),
+		Type:           reflect.TypeOf(new(Type)),
+		Implementation: reflect.TypeOf(new(Type)),
+		Dependencies:   fields.dependencies,
+		Optional:       fields.optional,
+		Names:          fields.names,
+		NewInstance:    getPointerInstance[Type](fields.indexes),
 	}, nil
 }
 
 // Interface constructor that would use *Type as implementation
 // and automatically fill public fields using registered constructors.
+// Go type parameters can not be variadic, so additional interfaces are not type parameters of I,
+// use As option to bind the same *Type instance to them:
+//
+//	tinysl.Add(tinysl.Singleton, tinysl.I[UserRepo, postgresStore], tinysl.As[OrderRepo](), tinysl.As[io.Closer]())
+//
+// Service is validated to implement each of them when it is added.
 func I[Interface, Type any]() (propertyFiller, error) {
 	p := reflect.TypeOf(new(Type))
 	t := p.Elem()
@@ -85,45 +93,256 @@ func I[Interface, Type any]() (propertyFiller, error) {
 		return propertyFiller{}, newIError(ErrITDoesNotImplementI, i, t)
 	}
 
-	filedIndex := 0
-	fields := make(map[int]int)
-	dependencies := make([]string, 0, 1)
+	fields, err := publicFields(t)
+	if err != nil {
+		return propertyFiller{}, newIError(err, i, t)
+	}
 
+	return propertyFiller{
+		Type:           reflect.TypeOf(new(Interface)).Elem(),
+		Implementation: p,
+		Dependencies:   fields.dependencies,
+		Optional:       fields.optional,
+		Names:          fields.names,
+		NewInstance:    getPointerInstance[Type](fields.indexes),
+	}, nil
+}
+
+// Dependencies of struct resolved by its public fields.
+type structFields struct {
+	// field index by dependency index
+	indexes      map[int][]int
+	dependencies []reflect.Type
+	optional     map[int]bool
+	names        map[int]string
+}
+
+// Returns dependencies of public fields of struct t, including fields of embedded structs.
+// Fields tagged with `tinysl:"-"` are skipped.
+func publicFields(t reflect.Type) (structFields, error) {
+	fields := structFields{
+		indexes:      make(map[int][]int),
+		dependencies: make([]reflect.Type, 0, 1),
+		optional:     make(map[int]bool),
+		names:        make(map[int]string),
+	}
+
+	return fields, fields.add(t, nil)
+}
+
+func (fields *structFields) add(t reflect.Type, index []int) error {
 	for i := 0; i < t.NumField(); i++ {
-		if !t.Field(i).IsExported() {
+		field := t.Field(i)
+		fieldIndex := append(slices.Clone(index), i)
+
+		switch {
+		case field.Anonymous && (field.Type == inType || field.Type == outType):
 			continue
+		case field.Anonymous && field.Type.Kind() == reflect.Struct && field.Tag.Get("tinysl") == "":
+			if err := fields.add(field.Type, fieldIndex); err != nil {
+				return err
+			}
+
+			continue
+		case !field.IsExported():
+			continue
+		}
+
+		tag, err := parseTag(field.Tag.Get("tinysl"))
+		if err != nil {
+			return newTagError(err, t, field.Name)
+		}
+
+		if tag.skip {
+			continue
+		}
+
+		if tag.group && field.Type.Kind() != reflect.Slice {
+			return newTagError(ErrGroupTagNotSlice, t, field.Name)
+		}
+
+		dependencyIndex := len(fields.dependencies)
+		if tag.optional {
+			fields.optional[dependencyIndex] = true
+		}
+
+		if tag.name != "" {
+			fields.names[dependencyIndex] = tag.name
 		}
 
-		dependencies = append(dependencies, t.Field(i).Type.String())
-		fields[filedIndex] = i
-		filedIndex++
+		fields.dependencies = append(fields.dependencies, field.Type)
+		fields.indexes[dependencyIndex] = fieldIndex
 	}
 
-	return propertyFiller{
-		Type:         reflect.TypeOf(new(Interface)).Elem(),
-		Dependencies: dependencies,
-		NewInstance:  getPointerInstance[Type](fields),
-	}, nil
+	return nil
 }
 
-func getValueInstance[T any](fields map[int]int) func(...any) (any, error) {
+type fieldTag struct {
+	name     string
+	optional bool
+	group    bool
+	skip     bool
+}
+
+// Parses `tinysl:"-"` tag or comma separated options of `tinysl:"..."` tag:
+// optional, name=<name> and group or group=<name>.
+func parseTag(tag string) (fieldTag, error) {
+	var result fieldTag
+	if tag == "-" {
+		result.skip = true
+		return result, nil
+	}
+
+	for _, option := range strings.Split(tag, ",") {
+		switch option = strings.TrimSpace(option); {
+		case option == "":
+		case option == "optional":
+			result.optional = true
+		case option == "group":
+			result.group = true
+		case strings.HasPrefix(option, "group="):
+			result.group, result.name = true, strings.TrimPrefix(option, "group=")
+		case strings.HasPrefix(option, "name="):
+			result.name = strings.TrimPrefix(option, "name=")
+		default:
+			return result, ErrUnknownTagOption
+		}
+	}
+
+	return result, nil
+}
+
+// Out embedded in struct makes it a result object.
+// Each public field of result object returned by constructor is registered as a separate service.
+type Out struct{}
+
+type resultField struct {
+	index       int
+	serviceType reflect.Type
+	fieldTag
+}
+
+// Returns public fields of result object.
+// Field tagged with `tinysl:"name=..."` is registered with name,
+// with `tinysl:"group"` or `tinysl:"group=..."` as a member of (named) group.
+func resultFields(t reflect.Type) ([]resultField, error) {
+	fields := make([]resultField, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() || field.Anonymous && field.Type == outType {
+			continue
+		}
+
+		tag, err := parseTag(field.Tag.Get("tinysl"))
+		if err != nil {
+			return nil, newTagError(err, t, field.Name)
+		}
+
+		if tag.optional {
+			return nil, newTagError(ErrOptionalResultField, t, field.Name)
+		}
+
+		fields = append(fields, resultField{index: i, serviceType: field.Type, fieldTag: tag})
+	}
+
+	return fields, nil
+}
+
+func isResultObject(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct && embeds(t, outType)
+}
+
+func isParameterObject(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct && embeds(t, inType)
+}
+
+func embeds(t, marker reflect.Type) bool {
+	for i := 0; i < t.NumField(); i++ {
+		if field := t.Field(i); field.Anonymous && field.Type == marker {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Returns constructor taking public fields of parameter objects as separate parameters,
+// parameter objects are assembled from them before original constructor is called.
+func withParameterObjects(constructor any, objects map[int]structFields) any {
+	fn := reflect.ValueOf(constructor)
+	t := fn.Type()
+
+	in := make([]reflect.Type, 0, t.NumIn())
+	for i := 0; i < t.NumIn(); i++ {
+		if object, ok := objects[i]; ok {
+			in = append(in, object.dependencies...)
+			continue
+		}
+
+		in = append(in, t.In(i))
+	}
+
+	out := make([]reflect.Type, t.NumOut())
+	for i := range out {
+		out[i] = t.Out(i)
+	}
+
+	return reflect.MakeFunc(
+		reflect.FuncOf(in, out, t.IsVariadic()),
+		func(args []reflect.Value) []reflect.Value {
+			params := make([]reflect.Value, t.NumIn())
+			for i := range params {
+				object, ok := objects[i]
+				if !ok {
+					params[i], args = args[0], args[1:]
+					continue
+				}
+
+				params[i] = reflect.New(t.In(i)).Elem()
+				for j := range object.dependencies {
+					params[i].FieldByIndex(object.indexes[j]).Set(args[j])
+				}
+
+				args = args[len(object.dependencies):]
+			}
+
+			if t.IsVariadic() {
+				return fn.CallSlice(params)
+			}
+
+			return fn.Call(params)
+		},
+	).Interface()
+}
+
+func getValueInstance[T any](fields map[int][]int) func(...any) (any, error) {
 	return func(values ...any) (any, error) {
 		p := reflect.ValueOf(new(T)).Elem()
 
 		for i, v := range values {
-			p.Field(fields[i]).Set(reflect.ValueOf(v))
+			// absent optional dependency keeps zero value
+			if v == nil {
+				continue
+			}
+
+			p.FieldByIndex(fields[i]).Set(reflect.ValueOf(v))
 		}
 
 		return p.Interface(), nil
 	}
 }
 
-func getPointerInstance[T any](fields map[int]int) func(...any) (any, error) {
+func getPointerInstance[T any](fields map[int][]int) func(...any) (any, error) {
 	return func(values ...any) (any, error) {
 		p := reflect.ValueOf(new(T)).Elem()
 
 		for i, v := range values {
-			p.Field(fields[i]).Set(reflect.ValueOf(v))
+			// absent optional dependency keeps zero value
+			if v == nil {
+				continue
+			}
+
+			p.FieldByIndex(fields[i]).Set(reflect.ValueOf(v))
 		}
 
 		return p.Addr().Interface(), nil
@@ -40,7 +40,44 @@ var _ = Describe("Constructor", func() {
 			Expect(constructor.Type).
 				To(Equal(reflect.TypeOf(ServiceWithPublicFields{})))
 			Expect(constructor.Dependencies).
-				To(Equal([]string{"tinysl_test.NameService"}))
+				To(Equal([]reflect.Type{reflect.TypeOf((*NameService)(nil)).Elem()}))
+		})
+	})
+
+	Context("Tags", func() {
+		It("should skip, name and recurse into embedded structs", func() {
+			constructor, err := tinysl.T[ServiceWithTags]()
+
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(constructor.Dependencies).
+				To(Equal([]reflect.Type{
+					reflect.TypeOf(new(Hero)),
+					reflect.TypeOf(new(NameService)).Elem(),
+					reflect.TypeOf([]Announcer{}),
+				}))
+			Expect(constructor.Optional).To(Equal(map[int]bool{0: true}))
+			Expect(constructor.Names).To(Equal(map[int]string{1: "hero", 2: "heroes"}))
+		})
+
+		It("should return error on invalid tag", func() {
+			_, err := tinysl.T[ServiceWithInvalidTag]()
+
+			Expect(err).Should(HaveOccurred())
+			Expect(err).Should(BeAssignableToTypeOf(new(tinysl.TError)))
+			Expect(errors.Unwrap(err)).Should(BeAssignableToTypeOf(new(tinysl.TagError)))
+			Expect(errors.Is(err, tinysl.ErrUnknownTagOption)).To(BeTrue())
+
+			_, err = tinysl.P[ServiceWithInvalidTag]()
+
+			Expect(err).Should(HaveOccurred())
+			Expect(err).Should(BeAssignableToTypeOf(new(tinysl.PError)))
+			Expect(errors.Is(err, tinysl.ErrUnknownTagOption)).To(BeTrue())
+
+			_, err = tinysl.I[NameService, ServiceWithInvalidTag]()
+
+			Expect(err).Should(HaveOccurred())
+			Expect(err).Should(BeAssignableToTypeOf(new(tinysl.IError)))
+			Expect(errors.Is(err, tinysl.ErrUnknownTagOption)).To(BeTrue())
 		})
 	})
 
@@ -74,7 +111,7 @@ var _ = Describe("Constructor", func() {
 			Expect(constructor.Type).
 				To(Equal(reflect.TypeOf(&ServiceWithPublicFields{})))
 			Expect(constructor.Dependencies).
-				To(Equal([]string{"tinysl_test.NameService"}))
+				To(Equal([]reflect.Type{reflect.TypeOf((*NameService)(nil)).Elem()}))
 		})
 	})
 
@@ -128,7 +165,7 @@ var _ = Describe("Constructor", func() {
 			Expect(constructor.Type).
 				To(Equal(reflect.TypeOf(new(HelloService)).Elem()))
 			Expect(constructor.Dependencies).
-				To(Equal([]string{"tinysl_test.NameService"}))
+				To(Equal([]reflect.Type{reflect.TypeOf((*NameService)(nil)).Elem()}))
 		})
 	})
 })
@@ -3,8 +3,11 @@ package tinysl
 import (
 	"context"
 	"fmt"
+	"iter"
+	"maps"
 	"reflect"
 	"slices"
+	"strings"
 	"sync"
 	"sync/atomic"
 )
@@ -12,6 +15,11 @@ import (
 const (
 	service   string = "service"
 	decorator string = "decorator"
+	group     string = "group"
+	mapEntry  string = "mapEntry"
+	factory   string = "factory"
+	// service used only as dependency of consumers registered WhenBuilding
+	contextual string = "contextual"
 )
 
 var _ Container = new(container)
@@ -19,6 +27,11 @@ var _ Container = new(container)
 type ContainerConfiguration struct {
 	Ctx                         context.Context
 	SilenceUseSingletonWarnings bool
+	ResolveByAssignability      bool
+	// ServiceLocator child container falls back to
+	parent ServiceLocator
+	// Container child container falls back to
+	base *container
 }
 
 type ContainerOption func(*ContainerConfiguration)
@@ -29,6 +42,10 @@ var (
 	}
 
 	SilenceUseSingletonWarnings ContainerOption = func(opt *ContainerConfiguration) { opt.SilenceUseSingletonWarnings = true }
+
+	// Resolves interface that is not registered to the only registered service implementing it.
+	// Dependencies are resolved by ServiceLocator(), several implementations are reported as AmbiguousDependencyError.
+	ResolveByAssignability ContainerOption = func(opt *ContainerConfiguration) { opt.ResolveByAssignability = true }
 )
 
 // Returns new Container.
@@ -41,12 +58,137 @@ func New(opts ...ContainerOption) Container {
 		opt(&conf)
 	}
 
-	return newContainer(conf.Ctx, conf.SilenceUseSingletonWarnings)
+	return newContainer(conf)
 }
 
 // Creates new Container, adds constructor and returns newly-created container.
-func Add(lifetime Lifetime, constructor any) Container {
-	return New().Add(lifetime, constructor)
+func Add(lifetime Lifetime, constructor any, opts ...RegistrationOption) Container {
+	return New().Add(lifetime, constructor, opts...)
+}
+
+// Creates new Container, adds factory constructor and returns newly-created container.
+func AddFactory(constructor any, opts ...RegistrationOption) Container {
+	return New().AddFactory(constructor, opts...)
+}
+
+// Creates new Container, adds value and returns newly-created container.
+func AddValue(value any, opts ...RegistrationOption) Container {
+	return New().AddValue(value, opts...)
+}
+
+// Creates new Container, adds provider methods and returns newly-created container.
+func AddProvider(provider any) Container {
+	return New().AddProvider(provider)
+}
+
+// Implemented by provider registered with AddProvider to choose lifetimes of its methods by their names.
+type ProviderLifetimes interface {
+	Lifetimes() map[string]Lifetime
+}
+
+type RegistrationConfiguration struct {
+	NamedDependencies map[reflect.Type][]string
+	Aliases           []reflect.Type
+	Consumers         []reflect.Type
+	Cleanup           Cleanup
+	Name              string
+	MapKey            string
+	GroupMember       bool
+	MapEntry          bool
+	Default           bool
+	InnermostScope    bool
+}
+
+type RegistrationOption func(*RegistrationConfiguration)
+
+var (
+	WithName = func(name string) RegistrationOption {
+		return func(opt *RegistrationConfiguration) { opt.Name = name }
+	}
+
+	// Registers constructor as a member of group of its service type T
+	// (combined with WithName, of group with that name).
+	// All group members are injected as []T or ...T dependency and can be retrieved with GetAll.
+	AsGroupMember RegistrationOption = func(opt *RegistrationConfiguration) { opt.GroupMember = true }
+
+	// Registers constructor as an entry of map[string]T with key, where T is its service type
+	// (combined with WithName, of map with that name).
+	// Map is injected as map[string]T dependency and can be retrieved with Get[map[string]T].
+	WithMapKey = func(key string) RegistrationOption {
+		return func(opt *RegistrationConfiguration) { opt.MapEntry, opt.MapKey = true, key }
+	}
+
+	// Registers default constructor, value or factory, that is used only if there is no regular registration
+	// of the same type and name (group or map of the same type and name for group members and map entries)
+	// when ServiceLocator() is called, regardless of the order of registrations.
+	AsDefault RegistrationOption = func(opt *RegistrationConfiguration) { opt.Default = true }
+
+	// Resolves PerContext service from the innermost scope created with NewScope,
+	// instead of the outermost one, so each nested scope gets its own instance.
+	PerInnermostScope RegistrationOption = func(opt *RegistrationConfiguration) { opt.InnermostScope = true }
+
+	// Cleanup function of value registered with AddValue, called same as Singleton cleanup.
+	WithCleanup = func(cleanup Cleanup) RegistrationOption {
+		return func(opt *RegistrationConfiguration) { opt.Cleanup = cleanup }
+	}
+)
+
+// Resolves constructor dependency of type T using service registered with name.
+// Used once, name is applied to every dependency of type T.
+// Used several times for the same T, names are applied to dependencies of type T in order of parameters,
+// so one constructor can take several differently named T's.
+func WithNamedDependency[T any](name string) RegistrationOption {
+	return func(opt *RegistrationConfiguration) {
+		if opt.NamedDependencies == nil {
+			opt.NamedDependencies = make(map[reflect.Type][]string)
+		}
+
+		t := reflect.TypeOf(new(T)).Elem()
+		opt.NamedDependencies[t] = append(opt.NamedDependencies[t], name)
+	}
+}
+
+// Registers constructor or value used instead of registration of the same type and name
+// only as a dependency of services of type T, can be used several times to add more consumers.
+// Dependencies are chosen by ServiceLocator(), so it does not affect Get.
+func WhenBuilding[T any]() RegistrationOption {
+	return func(opt *RegistrationConfiguration) {
+		opt.Consumers = append(opt.Consumers, reflect.TypeOf(new(T)).Elem())
+	}
+}
+
+// Binds service to interface I in addition to its own type.
+// Service and all its interfaces share the same instance.
+func As[I any]() RegistrationOption {
+	return func(opt *RegistrationConfiguration) {
+		opt.Aliases = append(opt.Aliases, reflect.TypeOf(new(I)).Elem())
+	}
+}
+
+func newRegistrationConfiguration(opts []RegistrationOption) RegistrationConfiguration {
+	var conf RegistrationConfiguration
+	for _, opt := range opts {
+		opt(&conf)
+	}
+
+	return conf
+}
+
+func (conf RegistrationConfiguration) role() (string, error) {
+	switch {
+	case conf.GroupMember && conf.MapEntry:
+		return "", ErrGroupMemberAndMapEntry
+	case len(conf.Consumers) > 0 && (conf.GroupMember || conf.MapEntry || len(conf.Aliases) > 0):
+		return "", ErrWhenBuildingUnsupported
+	case len(conf.Consumers) > 0:
+		return contextual, nil
+	case conf.GroupMember:
+		return group, nil
+	case conf.MapEntry:
+		return mapEntry, nil
+	default:
+		return service, nil
+	}
 }
 
 type constructorType int
@@ -57,43 +199,115 @@ const (
 	withErrorAndCleanUp
 )
 
+type serviceKey struct {
+	serviceType reflect.Type
+	name        string
+	// Module that registered service without exporting it.
+	// Services that are not exported by different modules do not collide.
+	module string
+}
+
+func (key serviceKey) String() string {
+	if key.name == "" {
+		return key.serviceType.String()
+	}
+
+	return fmt.Sprintf("%s named %q", key.serviceType, key.name)
+}
+
 type record struct {
 	constructor      any
-	typeName         string
+	serviceType      reflect.Type
+	name             string
 	constructorType  constructorType
 	lifetime         Lifetime
 	id               int32
 	dependsOnContext bool
+	variadic         bool
+	value            bool
+	// Field of result object is cleaned up together with result object.
+	resultField bool
+	// Optional dependencies by their index with type constructor takes them as,
+	// which is Optional[T] or T for optional fields of T, P and I.
+	optional map[int]reflect.Type
+	// Lazy dependencies by their index with type constructor takes them as, which is Lazy[T] or Provider[T].
+	lazy map[int]reflect.Type
+	// Arguments type of factory constructor.
+	args reflect.Type
+	// Name of module that registered service.
+	module string
+	// Registered by module that does not export it.
+	private bool
+	// Registered with AsDefault and used since there is no regular registration.
+	isDefault bool
+	// Regular registration used instead of registration with AsDefault.
+	overridesDefault bool
+	// Service of parent ServiceLocator resolved by it.
+	inherited bool
+	// Types of services record is used to build, if it is registered WhenBuilding them.
+	consumers []reflect.Type
+	// PerContext service resolved from the innermost scope.
+	innermost bool
+}
+
+// Reports if service can be resolved after dependency is cleaned up.
+// PerContext services resolved from the outermost scope outlive ones resolved from the innermost scope.
+func (r record) outlives(dependency record) bool {
+	return r.lifetime > dependency.lifetime ||
+		r.lifetime == PerContext && dependency.lifetime == PerContext && !r.innermost && dependency.innermost
+}
+
+func (r record) key() serviceKey {
+	key := serviceKey{serviceType: r.serviceType, name: r.name}
+	if r.private {
+		key.module = r.module
+	}
+
+	return key
 }
+
 type containerRecord struct {
-	dependencies []string
+	mapKey string
+	// Result object record the field record belongs to.
+	resultObject *containerRecord
+	dependencies []serviceKey
 	record
 }
 
-func newContainer(ctx context.Context, silenceUseSingletonWarnings bool) *container {
+type containerKey struct {
+	serviceKey
+	role string
+}
+
+func newContainer(conf ContainerConfiguration) *container {
 	return &container{
-		ctx:                       ctx,
-		constructors:              make(map[[2]string][]*containerRecord),
-		ignoreScopeAnalyzerErrors: silenceUseSingletonWarnings,
+		ctx:                       conf.Ctx,
+		constructors:              make(map[containerKey][]*containerRecord),
+		ignoreScopeAnalyzerErrors: conf.SilenceUseSingletonWarnings,
+		resolveByAssignability:    conf.ResolveByAssignability,
+		parent:                    conf.parent,
+		base:                      conf.base,
 		err:                       &atomic.Value{},
-		nextSingletonID:           0,
-		nextPerContextID:          0,
-		nextTransientID:           0,
 	}
 }
 
 type container struct {
-	ctx                       context.Context
-	err                       *atomic.Value
-	constructors              map[[2]string][]*containerRecord
-	constructorsRWM           sync.RWMutex
+	ctx          context.Context
+	err          *atomic.Value
+	constructors map[containerKey][]*containerRecord
+	modules      map[string]ModuleConfiguration
+	// registrations with AsDefault
+	defaults        *container
+	constructorsRWM sync.RWMutex
+	parent          ServiceLocator
+	// Container child container falls back to
+	base                      *container
 	ignoreScopeAnalyzerErrors bool
-	nextSingletonID           int32
-	nextPerContextID          int32
-	nextTransientID           int32
+	resolveByAssignability    bool
+	isDefaults                bool
 }
 
-func (c *container) Add(lifetime Lifetime, constructor any) Container {
+func (c *container) Add(lifetime Lifetime, constructor any, opts ...RegistrationOption) Container {
 	if errVal := c.err.Load(); errVal != nil {
 		return c
 	}
@@ -105,10 +319,32 @@ func (c *container) Add(lifetime Lifetime, constructor any) Container {
 		return c
 	}
 
+	conf := newRegistrationConfiguration(opts)
+
+	if conf.Default && !c.isDefaults {
+		return c.addDefault(func(defaults Container) { defaults.Add(lifetime, constructor, opts...) })
+	}
+
+	if conf.InnermostScope && lifetime != PerContext {
+		c.err.Store(newBadConstructorError(ErrInnermostScopeNotPerContext, reflect.TypeOf(constructor)))
+		return c
+	}
+
+	if conf.Cleanup != nil {
+		c.err.Store(newBadConstructorError(ErrCleanupWithoutValue, reflect.TypeOf(constructor)))
+		return c
+	}
+
+	role, err := conf.role()
+	if err != nil {
+		c.err.Store(newBadConstructorError(err, reflect.TypeOf(constructor)))
+		return c
+	}
+
 	// Check if constructor returns Constructor type
 	construct, ok := constructor.(func() (propertyFiller, error))
 	if ok {
-		return c.addPropertyFiller(lifetime, service, construct)
+		return c.addPropertyFiller(lifetime, role, construct, conf)
 	}
 
 	t := reflect.TypeOf(constructor)
@@ -119,11 +355,15 @@ func (c *container) Add(lifetime Lifetime, constructor any) Container {
 		return c
 	}
 
+	if isResultObject(t.Out(0)) {
+		return c.addResultObject(lifetime, role, constructor, cType, conf)
+	}
+
 	c.constructorsRWM.Lock()
 	defer c.constructorsRWM.Unlock()
 
-	serviceType := t.Out(0).String()
-	if _, ok := c.constructors[[2]string{serviceType, service}]; ok {
+	key := serviceKey{serviceType: t.Out(0), name: conf.Name}
+	if _, ok := c.constructors[containerKey{key, service}]; ok && role == service {
 		c.err.Store(newBadConstructorError(ErrDuplicateConstructor, t))
 		return c
 	}
@@ -133,297 +373,1273 @@ func (c *container) Add(lifetime Lifetime, constructor any) Container {
 			constructorType: cType,
 			lifetime:        lifetime,
 			constructor:     constructor,
-			typeName:        serviceType,
+			serviceType:     key.serviceType,
+			name:            key.name,
+			variadic:        t.IsVariadic(),
+			consumers:       conf.Consumers,
+			innermost:       conf.InnermostScope,
 		},
+		mapKey: conf.MapKey,
+	}
+
+	if err := fillDependencies(lifetime, t, t.NumIn(), r); err != nil {
+		c.err.Store(err)
+		return c
+	}
+
+	if err := nameDependencies(role, conf, r); err != nil {
+		c.err.Store(newBadConstructorError(err, t))
+		return c
+	}
+
+	if err := c.checkConsumers(key, role, r); err != nil {
+		c.err.Store(newBadConstructorError(err, t))
+		return c
 	}
 
-	if err := fillDependencies(lifetime, t, r); err != nil {
+	if err := c.addAliases(key.serviceType, role, conf, r); err != nil {
 		c.err.Store(err)
 		return c
 	}
 
-	r.id = c.nextID(lifetime)
-	c.constructors[[2]string{serviceType, service}] = []*containerRecord{r}
+	c.constructors[containerKey{key, role}] = append(c.constructors[containerKey{key, role}], r)
 
 	return c
 }
 
-func (c *container) Decorate(lifetime Lifetime, constructor any) Container {
+func (c *container) AddFactory(constructor any, opts ...RegistrationOption) Container {
 	if errVal := c.err.Load(); errVal != nil {
 		return c
 	}
 
-	if lifetime != Singleton &&
-		lifetime != PerContext &&
-		lifetime != Transient {
-		c.err.Store(LifetimeUnsupportedError(lifetime.String()))
-		return c
-	}
+	conf := newRegistrationConfiguration(opts)
+	t := reflect.TypeOf(constructor)
 
-	// Check if constructor returns Constructor type
-	construct, ok := constructor.(func() (propertyFiller, error))
-	if ok {
-		return c.addPropertyFiller(lifetime, decorator, construct)
+	if conf.Default && !c.isDefaults {
+		return c.addDefault(func(defaults Container) { defaults.AddFactory(constructor, opts...) })
 	}
 
-	// Regular constructor
-	t := reflect.TypeOf(constructor)
+	if conf.GroupMember || conf.MapEntry || conf.Cleanup != nil || len(conf.Aliases) > 0 || len(conf.Consumers) > 0 ||
+		conf.InnermostScope {
+		c.err.Store(newBadConstructorError(ErrFactoryUnsupportedOption, t))
+		return c
+	}
 
-	cType, err := getConstructorType(lifetime, t)
+	// factory instance is not cached, but it can have cleanup and depend on context.Context same as PerContext
+	cType, err := getConstructorType(PerContext, t)
 	if err != nil {
 		c.err.Store(err)
 		return c
 	}
 
+	numIn := t.NumIn()
+	if numIn == 0 || numIn == 1 && t.In(0).Implements(contextInterface) {
+		c.err.Store(newBadConstructorError(ErrFactoryWithoutArgs, t))
+		return c
+	}
+
 	c.constructorsRWM.Lock()
 	defer c.constructorsRWM.Unlock()
 
-	serviceType := t.Out(0).String()
+	key := serviceKey{serviceType: t.Out(0), name: conf.Name}
 	r := &containerRecord{
 		record: record{
 			constructorType: cType,
-			lifetime:        lifetime,
+			lifetime:        Transient,
 			constructor:     constructor,
-			typeName:        serviceType,
+			serviceType:     key.serviceType,
+			name:            key.name,
+			variadic:        t.IsVariadic(),
+			args:            t.In(numIn - 1),
 		},
 	}
 
-	if err := fillDependencies(lifetime, t, r); err != nil {
+	for _, registered := range c.constructors[containerKey{key, factory}] {
+		if registered.args == r.args {
+			c.err.Store(newBadConstructorError(ErrDuplicateConstructor, t))
+			return c
+		}
+	}
+
+	if err := fillDependencies(PerContext, t, numIn-1, r); err != nil {
 		c.err.Store(err)
 		return c
 	}
 
-	if !slices.Contains(r.dependencies, serviceType) {
-		c.err.Store(newBadConstructorError(ErrDecoratorBadDependency, t))
+	if err := nameDependencies(factory, conf, r); err != nil {
+		c.err.Store(newBadConstructorError(err, t))
 		return c
 	}
 
-	r.id = c.nextID(lifetime)
-	c.constructors[[2]string{serviceType, decorator}] = append(c.constructors[[2]string{serviceType, decorator}], r)
+	c.constructors[containerKey{key, factory}] = append(c.constructors[containerKey{key, factory}], r)
 
 	return c
 }
 
-func (c *container) Replace(constructor any) Container {
+func (c *container) AddValue(value any, opts ...RegistrationOption) Container {
 	if errVal := c.err.Load(); errVal != nil {
 		return c
 	}
 
-	var serviceType string
-	if construct, ok := constructor.(func() (propertyFiller, error)); ok {
-		constructor, err := construct()
-		if err != nil {
-			c.err.Store(err)
-
-			return c
-		}
-
-		t := constructor.Type
-		serviceType = t.String()
-	} else {
-		t := reflect.TypeOf(constructor)
+	conf := newRegistrationConfiguration(opts)
 
-		if t.Kind() != reflect.Func {
-			c.err.Store(newBadConstructorError(ErrConstructorNotAFunction, t))
+	if conf.Default && !c.isDefaults {
+		return c.addDefault(func(defaults Container) { defaults.AddValue(value, opts...) })
+	}
 
-			return c
-		}
+	t := reflect.TypeOf(value)
+	if t == nil {
+		c.err.Store(ErrNilValue)
+		return c
+	}
 
-		serviceType = t.Out(0).String()
+	if conf.InnermostScope {
+		c.err.Store(newBadConstructorError(ErrInnermostScopeNotPerContext, t))
+		return c
+	}
 
+	role, err := conf.role()
+	if err != nil {
+		c.err.Store(newBadConstructorError(err, t))
+		return c
 	}
 
 	c.constructorsRWM.Lock()
-	s, ok := c.constructors[[2]string{serviceType, service}]
+	defer c.constructorsRWM.Unlock()
 
-	if !ok || len(s) == 0 {
-		c.err.Store(newBadConstructorError(newConstructorNotFoundError(serviceType), reflect.TypeOf(constructor)))
-		c.constructorsRWM.Unlock()
+	key := serviceKey{serviceType: t, name: conf.Name}
+	if _, ok := c.constructors[containerKey{key, service}]; ok && role == service {
+		c.err.Store(newBadConstructorError(ErrDuplicateConstructor, t))
 		return c
 	}
 
-	delete(c.constructors, [2]string{serviceType, service})
-	c.constructorsRWM.Unlock()
-
-	return c.Add(s[0].lifetime, constructor)
-}
-
-func (c *container) ServiceLocator() (ServiceLocator, error) {
-	c.constructorsRWM.RLock()
-	defer c.constructorsRWM.RUnlock()
-
-	if errVal := c.err.Load(); errVal != nil {
-		return nil, errVal.(error)
+	constructor, cType := newValueConstructor(value, t, conf.Cleanup)
+	r := &containerRecord{
+		record: record{
+			constructorType: cType,
+			lifetime:        Singleton,
+			constructor:     constructor,
+			serviceType:     key.serviceType,
+			name:            key.name,
+			value:           true,
+			consumers:       conf.Consumers,
+		},
+		mapKey: conf.MapKey,
 	}
 
-	for key, records := range c.constructors {
-		for _, record := range records {
-			shouldBeSingleton, err := c.canResolveDependencies(*record, key[1])
-			if err != nil {
-				return nil, err
-			}
-
-			if !c.ignoreScopeAnalyzerErrors && shouldBeSingleton {
-				logger().Error(
-					"your dependency hierarchy can be optimised",
-					"error", fmt.Errorf("%s %s should be a Singleton", record.lifetime, record.typeName),
-				)
-			}
-		}
+	if err := nameDependencies(role, conf, r); err != nil {
+		c.err.Store(newBadConstructorError(err, t))
+		return c
 	}
 
-	return newLocator(c.ctx, containerRecordsToLocatorRecords(c.constructors), c.nextSingletonID, c.nextPerContextID), nil
-}
-
-func (c *container) canResolveDependencies(record containerRecord, role string, dependentServiceNames ...string) (bool, error) {
-	dependentServiceNames = append(dependentServiceNames, record.typeName)
-	shouldBeSingleton := record.lifetime < Singleton && !record.dependsOnContext
-
-	for _, dependency := range record.dependencies {
-		if dependency == contextDepName {
-			continue
-		}
-
-		rs, ok := c.constructors[[2]string{dependency, service}]
-
-		switch {
-		case role == decorator && dependency == record.typeName && !ok:
-			return false, newServiceBuilderError(
-				ErrDecoratorHasNothingToDecorate,
-				record.lifetime,
-				record.typeName,
-			)
-		case !ok:
-			return false, newServiceBuilderError(
-				newConstructorNotFoundError(dependency),
-				record.lifetime,
-				record.typeName,
-			)
-		}
-
-		for _, r := range rs {
-			if !c.ignoreScopeAnalyzerErrors && record.lifetime > r.lifetime {
-				return false, newServiceBuilderError(
-					newScopeHierarchyError(r.lifetime, r.typeName),
-					record.lifetime,
-					record.typeName,
-				)
-			}
-
-			if shouldBeSingleton {
-				shouldBeSingleton = r.lifetime == Singleton
-			}
-
-			for _, serviceName := range dependentServiceNames {
-				if role != decorator && serviceName == dependency {
-					return false, newServiceBuilderError(
-						newCircularDependencyError(record.constructor, dependency),
-						record.lifetime,
-						record.typeName,
-					)
-				}
-			}
+	if err := c.checkConsumers(key, role, r); err != nil {
+		c.err.Store(newBadConstructorError(err, t))
+		return c
+	}
 
-			_, err := c.canResolveDependencies(*r, service, dependentServiceNames...)
-			if err != nil {
-				return false, err
-			}
-		}
+	if err := c.addAliases(t, role, conf, r); err != nil {
+		c.err.Store(err)
+		return c
 	}
 
-	return shouldBeSingleton, nil
-}
+	c.constructors[containerKey{key, role}] = append(c.constructors[containerKey{key, role}], r)
 
-func (c *container) addPropertyFiller(lifetime Lifetime, role string, construct func() (propertyFiller, error)) Container {
-	constructor, err := construct()
-	if err != nil {
-		c.err.Store(err)
+	return c
+}
 
+func (c *container) AddProvider(provider any) Container {
+	if errVal := c.err.Load(); errVal != nil {
 		return c
 	}
 
-	t := constructor.Type
-	serviceType := t.String()
-	r := &containerRecord{
-		record: record{
-			constructorType: withError,
-			typeName:        serviceType,
-			lifetime:        lifetime,
-			constructor:     constructor.NewInstance,
-		},
-		dependencies: constructor.Dependencies,
+	v := reflect.ValueOf(provider)
+	if !v.IsValid() || v.Kind() == reflect.Pointer && v.IsNil() {
+		c.err.Store(newProviderError(ErrNilValue, reflect.TypeOf(provider), ""))
+		return c
 	}
 
-	switch role {
-	case service:
-		c.constructorsRWM.Lock()
-		defer c.constructorsRWM.Unlock()
+	t := v.Type()
+	p, hasLifetimes := provider.(ProviderLifetimes)
 
-		if _, ok := c.constructors[[2]string{serviceType, service}]; ok {
-			c.err.Store(newBadConstructorError(ErrDuplicateConstructor, t))
+	var lifetimes map[string]Lifetime
+	if hasLifetimes {
+		lifetimes = p.Lifetimes()
+	}
 
+	for name, lifetime := range lifetimes {
+		if _, ok := t.MethodByName(name); !ok || name == "Lifetimes" {
+			c.err.Store(newProviderError(ErrProviderUnknownMethod, t, name))
 			return c
 		}
 
-		c.constructors[[2]string{serviceType, service}] = []*containerRecord{r}
-	case decorator:
-		if !slices.Contains(constructor.Dependencies, serviceType) {
-			c.err.Store(newBadConstructorError(ErrDecoratorBadDependency, t))
-
+		if lifetime != Singleton && lifetime != PerContext && lifetime != Transient {
+			c.err.Store(newProviderError(LifetimeUnsupportedError(lifetime.String()), t, name))
 			return c
 		}
+	}
 
-		c.constructorsRWM.Lock()
-		defer c.constructorsRWM.Unlock()
+	constructors := 0
+	for i := 0; i < t.NumMethod(); i++ {
+		method := t.Method(i)
+		if hasLifetimes && method.Name == "Lifetimes" {
+			continue
+		}
 
-		c.constructors[[2]string{serviceType, decorator}] = append(c.constructors[[2]string{serviceType, decorator}], r)
+		lifetime, ok := lifetimes[method.Name]
+		if !ok {
+			lifetime = lifetimeByName(method.Name)
+		}
+
+		constructor := v.Method(i).Interface()
+		if _, err := getConstructorType(lifetime, reflect.TypeOf(constructor)); err != nil {
+			c.err.Store(newProviderError(err, t, method.Name))
+			return c
+		}
+
+		c.Add(lifetime, constructor)
+		constructors++
 	}
 
-	r.id = c.nextID(lifetime)
+	if constructors == 0 {
+		c.err.Store(newProviderError(ErrProviderWithoutMethods, t, ""))
+	}
 
 	return c
 }
 
-func (c *container) nextID(l Lifetime) (id int32) {
-	switch l {
-	case Singleton:
-		id = c.nextSingletonID
-		atomic.AddInt32(&c.nextSingletonID, 1)
-		return
-	case PerContext:
-		id = c.nextPerContextID
-		atomic.AddInt32(&c.nextPerContextID, 1)
-		return
-	case Transient:
-		id = c.nextTransientID
-		atomic.AddInt32(&c.nextTransientID, 1)
-		return
+// Provider method prefixed with Transient or PerContext is registered with that lifetime,
+// any other method is registered as a Singleton.
+func lifetimeByName(method string) Lifetime {
+	switch {
+	case strings.HasPrefix(method, Transient.String()):
+		return Transient
+	case strings.HasPrefix(method, PerContext.String()):
+		return PerContext
+	default:
+		return Singleton
 	}
-
-	panic(LifetimeUnsupportedError(l.String()))
 }
 
-func getConstructorType(lifetime Lifetime, t reflect.Type) (constructorType, error) {
-	// Regular constructor
-	cType := onlyService
+func (c *container) Decorate(lifetime Lifetime, constructor any, opts ...RegistrationOption) Container {
+	if errVal := c.err.Load(); errVal != nil {
+		return c
+	}
 
-	if t.Kind() != reflect.Func {
-		return cType, newConstructorUnsupportedError(t, lifetime)
+	if lifetime != Singleton &&
+		lifetime != PerContext &&
+		lifetime != Transient {
+		c.err.Store(LifetimeUnsupportedError(lifetime.String()))
+		return c
 	}
 
-	if t.IsVariadic() {
-		return cType, newBadConstructorError(ErrVariadicConstructor, t)
+	conf := newRegistrationConfiguration(opts)
+
+	if conf.Cleanup != nil {
+		c.err.Store(newBadConstructorError(ErrCleanupWithoutValue, reflect.TypeOf(constructor)))
+		return c
 	}
 
-	numIn := t.NumIn()
+	if conf.Default {
+		c.err.Store(newBadConstructorError(ErrDefaultUnsupported, reflect.TypeOf(constructor)))
+		return c
+	}
 
-	// Singleton cannot be based on any context, but PerContext and Transient can
-	if lifetime == Singleton && numIn > 0 && t.In(0).Implements(contextInterface) {
-		return cType, newConstructorUnsupportedError(t, lifetime)
+	if len(conf.Consumers) > 0 {
+		c.err.Store(newBadConstructorError(ErrWhenBuildingUnsupported, reflect.TypeOf(constructor)))
+		return c
 	}
 
-	switch t.NumOut() {
-	case 1:
+	if conf.InnermostScope && lifetime != PerContext {
+		c.err.Store(newBadConstructorError(ErrInnermostScopeNotPerContext, reflect.TypeOf(constructor)))
+		return c
+	}
+
+	// Check if constructor returns Constructor type
+	construct, ok := constructor.(func() (propertyFiller, error))
+	if ok {
+		return c.addPropertyFiller(lifetime, decorator, construct, conf)
+	}
+
+	// Regular constructor
+	t := reflect.TypeOf(constructor)
+
+	cType, err := getConstructorType(lifetime, t)
+	if err != nil {
+		c.err.Store(err)
+		return c
+	}
+
+	c.constructorsRWM.Lock()
+	defer c.constructorsRWM.Unlock()
+
+	key := serviceKey{serviceType: t.Out(0), name: conf.Name}
+	r := &containerRecord{
+		record: record{
+			constructorType: cType,
+			lifetime:        lifetime,
+			constructor:     constructor,
+			serviceType:     key.serviceType,
+			name:            key.name,
+			variadic:        t.IsVariadic(),
+		},
+	}
+
+	if err := fillDependencies(lifetime, t, t.NumIn(), r); err != nil {
+		c.err.Store(err)
+		return c
+	}
+
+	if err := nameDependencies(decorator, conf, r); err != nil {
+		c.err.Store(newBadConstructorError(err, t))
+		return c
+	}
+
+	if !slices.Contains(r.dependencies, key) {
+		c.err.Store(newBadConstructorError(ErrDecoratorBadDependency, t))
+		return c
+	}
+
+	c.constructors[containerKey{key, decorator}] = append(c.constructors[containerKey{key, decorator}], r)
+
+	return c
+}
+
+func (c *container) Replace(constructor any, opts ...RegistrationOption) Container {
+	if errVal := c.err.Load(); errVal != nil {
+		return c
+	}
+
+	conf := newRegistrationConfiguration(opts)
+
+	if conf.Default {
+		c.err.Store(newBadConstructorError(ErrDefaultUnsupported, reflect.TypeOf(constructor)))
+		return c
+	}
+
+	if len(conf.Consumers) > 0 {
+		c.err.Store(newBadConstructorError(ErrWhenBuildingUnsupported, reflect.TypeOf(constructor)))
+		return c
+	}
+
+	var serviceType reflect.Type
+	if construct, ok := constructor.(func() (propertyFiller, error)); ok {
+		constructor, err := construct()
+		if err != nil {
+			c.err.Store(err)
+
+			return c
+		}
+
+		serviceType = constructor.Type
+	} else {
+		t := reflect.TypeOf(constructor)
+
+		if t.Kind() != reflect.Func {
+			c.err.Store(newBadConstructorError(ErrConstructorNotAFunction, t))
+
+			return c
+		}
+
+		serviceType = t.Out(0)
+	}
+
+	key := serviceKey{serviceType: serviceType, name: conf.Name}
+
+	c.constructorsRWM.Lock()
+	s, ok := c.constructors[containerKey{key, service}]
+
+	if !ok || len(s) == 0 {
+		c.err.Store(newBadConstructorError(newConstructorNotFoundError(key.serviceType, key.name), reflect.TypeOf(constructor)))
+		c.constructorsRWM.Unlock()
+		return c
+	}
+
+	var aliases []reflect.Type
+	for k, records := range c.constructors {
+		// aliases are replaced together with the record they are bound to
+		if k.role == service && records[0] == s[0] {
+			if k.serviceKey != key {
+				aliases = append(aliases, k.serviceType)
+			}
+
+			delete(c.constructors, k)
+			continue
+		}
+
+		// fields are replaced together with result object they belong to
+		rest := slices.DeleteFunc(records, func(r *containerRecord) bool { return r.resultObject == s[0] })
+		if len(rest) == 0 {
+			delete(c.constructors, k)
+		} else if len(rest) != len(records) {
+			c.constructors[k] = rest
+		}
+	}
+	c.constructorsRWM.Unlock()
+
+	// replacement keeps interfaces of replaced service, unless it is bound with its own As options
+	if len(conf.Aliases) == 0 && len(aliases) > 0 {
+		opts = append(slices.Clone(opts), func(opt *RegistrationConfiguration) {
+			opt.Aliases = append(opt.Aliases, aliases...)
+		})
+	}
+
+	return c.Add(s[0].lifetime, constructor, opts...)
+}
+
+// Default registrations are kept in separate container and merged with regular ones by ServiceLocator().
+func (c *container) addDefault(register func(Container)) Container {
+	c.constructorsRWM.Lock()
+	defaults := c.defaultRegistrations()
+	c.constructorsRWM.Unlock()
+
+	register(defaults)
+
+	if errVal := defaults.err.Load(); errVal != nil {
+		c.err.Store(errVal)
+	}
+
+	return c
+}
+
+// Must be called with constructorsRWM locked, since ServiceLocator() reads defaults under it.
+func (c *container) defaultRegistrations() *container {
+	if c.defaults == nil {
+		c.defaults = newContainer(ContainerConfiguration{
+			Ctx:                         c.ctx,
+			SilenceUseSingletonWarnings: c.ignoreScopeAnalyzerErrors,
+			ResolveByAssignability:      c.resolveByAssignability,
+		})
+		c.defaults.isDefaults = true
+	}
+
+	return c.defaults
+}
+
+// Returns container with default registrations used for keys that have no regular registrations.
+// Container itself is not changed, so registrations can still be added after ServiceLocator() is called.
+func (c *container) withDefaults() *container {
+	if c.defaults == nil {
+		return c
+	}
+
+	c.defaults.constructorsRWM.RLock()
+	defer c.defaults.constructorsRWM.RUnlock()
+
+	constructors := maps.Clone(c.constructors)
+	// true for default registrations that are used, false for regular registrations that override them
+	isDefault := make(map[*containerRecord]bool)
+	for key, records := range c.defaults.constructors {
+		if regular, ok := constructors[key]; ok {
+			for _, r := range regular {
+				isDefault[r] = false
+			}
+
+			continue
+		}
+
+		for _, r := range records {
+			isDefault[r] = true
+		}
+
+		constructors[key] = records
+	}
+
+	// records are shared by ServiceLocators built concurrently, so flags are set on their copies
+	copies := make(map[*containerRecord]*containerRecord, len(isDefault))
+	for r, used := range isDefault {
+		cp := *r
+		cp.isDefault = used
+		cp.overridesDefault = !used
+		copies[r] = &cp
+	}
+
+	for _, cp := range copies {
+		if resultObject, ok := copies[cp.resultObject]; ok {
+			cp.resultObject = resultObject
+		}
+	}
+
+	for key, records := range constructors {
+		// aliases and fields are bound to the same copy as their records
+		records = slices.Clone(records)
+		for i, r := range records {
+			if cp, ok := copies[r]; ok {
+				records[i] = cp
+			}
+		}
+
+		constructors[key] = records
+	}
+
+	return c.view(constructors)
+}
+
+// Returns container with services of parent ServiceLocator that are not registered by child container.
+// They are resolved by parent, so parent instances are shared and scope hierarchy is checked with parent lifetimes.
+// Factories of parent are not used as dependencies, since they are resolved by factory constructors.
+func (c *container) withParent() *container {
+	if c.parent == nil {
+		return c
+	}
+
+	constructors := maps.Clone(c.constructors)
+	for _, binding := range c.parent.Bindings() {
+		key := serviceKey{serviceType: binding.ServiceType, name: binding.Name}
+		if _, ok := constructors[containerKey{key, service}]; ok || key.serviceType.Implements(factoryInterface) || binding.WhenBuilding != nil {
+			continue
+		}
+
+		if _, isCollection := c.collectionMembers(key); isCollection {
+			continue
+		}
+
+		constructors[containerKey{key, service}] = []*containerRecord{newParentRecord(c.parent, binding)}
+	}
+
+	return c.view(constructors)
+}
+
+// Returns container with the same configuration and constructors.
+func (c *container) view(constructors map[containerKey][]*containerRecord) *container {
+	return &container{
+		ctx:                       c.ctx,
+		err:                       c.err,
+		constructors:              constructors,
+		modules:                   c.modules,
+		defaults:                  c.defaults,
+		parent:                    c.parent,
+		base:                      c.base,
+		ignoreScopeAnalyzerErrors: c.ignoreScopeAnalyzerErrors,
+		resolveByAssignability:    c.resolveByAssignability,
+	}
+}
+
+func newParentRecord(parent ServiceLocator, binding Binding) *containerRecord {
+	t := binding.ServiceType
+	constructor := reflect.MakeFunc(
+		reflect.FuncOf([]reflect.Type{contextInterface}, []reflect.Type{t, errorInterface}, false),
+		func(args []reflect.Value) []reflect.Value {
+			ctx, _ := args[0].Interface().(context.Context)
+			result := []reflect.Value{reflect.New(t).Elem(), reflect.Zero(errorInterface)}
+
+			service, err := parent.GetNamed(ctx, t, binding.Name)
+			if err != nil {
+				result[1] = reflect.ValueOf(&err).Elem()
+				return result
+			}
+
+			result[0].Set(reflect.ValueOf(service))
+
+			return result
+		},
+	)
+
+	return &containerRecord{
+		record: record{
+			constructorType:  withError,
+			lifetime:         binding.Lifetime,
+			constructor:      constructor.Interface(),
+			serviceType:      t,
+			name:             binding.Name,
+			dependsOnContext: true,
+			inherited:        true,
+		},
+		dependencies: []serviceKey{{serviceType: contextInterface}},
+	}
+}
+
+func (c *container) ServiceLocator() (ServiceLocator, error) {
+	c.constructorsRWM.RLock()
+	defer c.constructorsRWM.RUnlock()
+
+	if errVal := c.err.Load(); errVal != nil {
+		return nil, errVal.(error)
+	}
+
+	view, err := c.withParent().withDefaults().withBase()
+	if err != nil {
+		return nil, err
+	}
+
+	return view.serviceLocator()
+}
+
+func (c *container) Child(opts ...ContainerOption) Container {
+	conf := ContainerConfiguration{
+		Ctx:                         c.ctx,
+		SilenceUseSingletonWarnings: c.ignoreScopeAnalyzerErrors,
+		ResolveByAssignability:      c.resolveByAssignability,
+	}
+
+	for _, opt := range opts {
+		opt(&conf)
+	}
+
+	conf.base = c
+
+	return newContainer(conf)
+}
+
+// Returns container with registrations of container child container falls back to.
+// Services registered by child are used instead of its ones, unless they are default registrations,
+// while group members, map entries, decorators and registrations WhenBuilding are added to its ones.
+func (c *container) withBase() (*container, error) {
+	if c.base == nil {
+		return c, nil
+	}
+
+	c.base.constructorsRWM.RLock()
+	defer c.base.constructorsRWM.RUnlock()
+
+	if errVal := c.base.err.Load(); errVal != nil {
+		return nil, errVal.(error)
+	}
+
+	base, err := c.base.withParent().withDefaults().withBase()
+	if err != nil {
+		return nil, err
+	}
+
+	constructors := maps.Clone(base.constructors)
+	for key, records := range c.constructors {
+		switch inherited := constructors[key]; {
+		case len(inherited) == 0:
+			constructors[key] = records
+		case key.role == service:
+			if !records[0].isDefault || inherited[0].isDefault {
+				constructors[key] = records
+			}
+		case key.role == factory:
+			inherited = slices.DeleteFunc(slices.Clone(inherited), func(r *containerRecord) bool {
+				return slices.ContainsFunc(records, func(own *containerRecord) bool { return own.args == r.args })
+			})
+			constructors[key] = append(inherited, records...)
+		default:
+			constructors[key] = append(slices.Clone(inherited), records...)
+		}
+	}
+
+	modules := maps.Clone(base.modules)
+	for name, m := range c.modules {
+		if _, ok := modules[name]; ok {
+			return nil, newModuleError(ErrDuplicateModule, name)
+		}
+
+		if modules == nil {
+			modules = make(map[string]ModuleConfiguration)
+		}
+
+		modules[name] = m
+	}
+
+	view := c.view(constructors)
+	view.modules = modules
+
+	return view, nil
+}
+
+func (c *container) serviceLocator() (ServiceLocator, error) {
+	if err := c.checkImports(); err != nil {
+		return nil, err
+	}
+
+	inferred, err := c.inferBindings()
+	if err != nil {
+		return nil, err
+	}
+
+	for key, records := range c.constructors {
+		if key.role == group || key.role == mapEntry {
+			collection := collectionKey(key)
+			if _, ok := c.constructors[containerKey{collection, service}]; ok {
+				return nil, newServiceBuilderError(ErrDuplicateConstructor, records[0].lifetime, collection.String())
+			}
+
+			if err := checkMapKeys(key, records); err != nil {
+				return nil, err
+			}
+		}
+
+		for _, record := range records {
+			if key.serviceKey != record.key() {
+				// aliases are checked with the record they are bound to
+				continue
+			}
+
+			shouldBeSingleton, err := c.canResolveDependencies(record, key.role, inferred)
+			if err != nil {
+				return nil, err
+			}
+
+			// factory builds new instance on each call
+			if !c.ignoreScopeAnalyzerErrors && shouldBeSingleton && key.role != factory {
+				logger().Error(
+					"your dependency hierarchy can be optimised",
+					"error", fmt.Errorf("%s %s should be a Singleton", record.lifetime, record.key()),
+				)
+			}
+		}
+	}
+
+	constructorsByType, records := containerRecordsToLocatorRecords(c.constructors, inferred)
+	c.hideModuleServices(constructorsByType, inferred)
+
+	l := newLocator(
+		c.ctx,
+		constructorsByType,
+		records,
+		slices.Collect(maps.Keys(inferred)),
+		c.resolveByAssignability,
+	)
+	l.parent = c.parent
+
+	for _, rec := range records {
+		if len(rec.consumers) > 0 {
+			l.contextual = append(l.contextual, rec)
+		}
+	}
+
+	return l, nil
+}
+
+func (c *container) canResolveDependencies(
+	record *containerRecord,
+	role string,
+	inferred map[serviceKey]*containerRecord,
+	dependents ...*containerRecord,
+) (bool, error) {
+	dependents = append(dependents, record)
+	shouldBeSingleton := record.lifetime < Singleton && !record.dependsOnContext
+
+	for i, dependency := range record.dependencies {
+		if dependency.serviceType == contextInterface {
+			continue
+		}
+
+		rs, ok := c.constructors[containerKey{dependency, service}]
+		if r, isContextual := contextualOf(c.constructors, record, dependency); isContextual {
+			rs, ok = []*containerRecord{r}, true
+		}
+
+		depName := dependency.String()
+		// service registered directly is exported with its type, other are exported with type of their records
+		direct := ok
+
+		if r, isInferred := inferred[dependency]; !ok && isInferred {
+			rs, ok = []*containerRecord{r}, true
+			depName = fmt.Sprintf("%s inferred as %s", dependency, r.key())
+		}
+
+		if f, isFactory := factoryOf(c.constructors, dependency); !ok && isFactory {
+			rs, ok = []*containerRecord{f}, true
+		}
+
+		if members, isCollection := c.collectionMembers(dependency); !ok && isCollection {
+			// group and map dependencies are resolved by each of their members
+			rs, ok = members, true
+		}
+
+		_, isOptional := record.optional[i]
+
+		switch {
+		case role == decorator && dependency == record.key() && !ok:
+			return false, newRecordBuilderError(
+				ErrDecoratorHasNothingToDecorate,
+				record.record,
+			)
+		case !ok && isOptional:
+			// absent optional dependency is passed as zero value
+			continue
+		case !ok && record.variadic && record.args == nil && i == len(record.dependencies)-1:
+			return false, newBadConstructorError(ErrVariadicConstructor, reflect.TypeOf(record.constructor))
+		case !ok:
+			if module, isPrivate := c.privateModule(dependency); isPrivate {
+				return false, newRecordBuilderError(newModuleVisibilityError(depName, module, false), record.record)
+			}
+
+			return false, newRecordBuilderError(
+				newConstructorNotFoundError(dependency.serviceType, dependency.name),
+				record.record,
+			)
+		}
+
+		for _, r := range rs {
+			exportedAs := r.serviceType
+			if direct {
+				exportedAs = dependency.serviceType
+			}
+
+			if err := c.checkVisibility(record.module, r.module, exportedAs, depName); err != nil {
+				return false, newRecordBuilderError(err, record.record)
+			}
+		}
+
+		if _, isLazy := record.lazy[i]; isLazy {
+			// lazy dependency is resolved after constructor is called with context.Context Lazy[T] is called with,
+			// so it neither violates scope hierarchy nor creates circular dependency
+			continue
+		}
+
+		for _, r := range rs {
+			if !c.ignoreScopeAnalyzerErrors && record.outlives(r.record) {
+				return false, newRecordBuilderError(
+					newScopeHierarchyError(r.lifetime, depName),
+					record.record,
+				)
+			}
+
+			if shouldBeSingleton {
+				shouldBeSingleton = r.lifetime == Singleton
+			}
+
+			// records are compared by identity, since same record can be registered under several keys
+			if role != decorator && slices.Contains(dependents, r) {
+				return false, newRecordBuilderError(
+					newCircularDependencyError(record.constructor, dependency.serviceType, dependency.name),
+					record.record,
+				)
+			}
+
+			_, err := c.canResolveDependencies(r, service, inferred, dependents...)
+			if err != nil {
+				return false, err
+			}
+		}
+	}
+
+	return shouldBeSingleton, nil
+}
+
+// Binds interface dependencies that are not registered to the only registered service implementing them.
+func (c *container) inferBindings() (map[serviceKey]*containerRecord, error) {
+	inferred := make(map[serviceKey]*containerRecord)
+	if !c.resolveByAssignability {
+		return inferred, nil
+	}
+
+	for key, records := range c.constructors {
+		for _, r := range records {
+			if key.serviceKey != r.key() {
+				continue
+			}
+
+			for _, dep := range r.dependencies {
+				if _, ok := inferred[dep]; ok || dep.serviceType.Kind() != reflect.Interface || dep.serviceType == contextInterface {
+					continue
+				}
+
+				if _, ok := c.constructors[containerKey{dep, service}]; ok {
+					continue
+				}
+
+				switch candidates := implementations(dep, c.services()); len(candidates) {
+				case 0:
+					// reported as not found by canResolveDependencies
+				case 1:
+					inferred[dep] = candidates[0]
+				default:
+					return nil, newRecordBuilderError(
+						newAmbiguousDependencyError(dep.serviceType, dep.name, serviceTypes(candidates)),
+						r.record,
+					)
+				}
+			}
+		}
+	}
+
+	return inferred, nil
+}
+
+// Iterates over services registered in container.
+func (c *container) services() iter.Seq2[serviceKey, *containerRecord] {
+	return func(yield func(serviceKey, *containerRecord) bool) {
+		for key, records := range c.constructors {
+			if key.role == service && !yield(key.serviceKey, records[0]) {
+				return
+			}
+		}
+	}
+}
+
+// Returns services implementing interface of key with the same name.
+// Aliases and inferred bindings are skipped, since they share the record of service they are bound to.
+func implementations[R interface{ key() serviceKey }](key serviceKey, services iter.Seq2[serviceKey, R]) []R {
+	var candidates []R
+	for k, r := range services {
+		if k != r.key() || k.name != key.name || !k.serviceType.Implements(key.serviceType) {
+			continue
+		}
+
+		candidates = append(candidates, r)
+	}
+
+	return candidates
+}
+
+func serviceTypes[R interface{ key() serviceKey }](records []R) []reflect.Type {
+	types := make([]reflect.Type, len(records))
+	for i, r := range records {
+		types[i] = r.key().serviceType
+	}
+
+	return types
+}
+
+// Returns members of group or map multi-binding the dependency can be resolved with.
+func (c *container) collectionMembers(key serviceKey) ([]*containerRecord, bool) {
+	var role string
+
+	switch t := key.serviceType; {
+	case t.Kind() == reflect.Slice:
+		role = group
+	case t.Kind() == reflect.Map && t.Key() == stringType:
+		role = mapEntry
+	default:
+		return nil, false
+	}
+
+	members, ok := c.constructors[containerKey{serviceKey{key.serviceType.Elem(), key.name, key.module}, role}]
+
+	return members, ok
+}
+
+// Returns factory constructor the Factory[Args, T] dependency is resolved with.
+func factoryOf(recordsMap map[containerKey][]*containerRecord, key serviceKey) (*containerRecord, bool) {
+	if !key.serviceType.Implements(factoryInterface) {
+		return nil, false
+	}
+
+	f := reflect.Zero(key.serviceType).Interface().(factoryDependency)
+	for _, r := range recordsMap[containerKey{serviceKey{f.serviceType(), key.name, key.module}, factory}] {
+		if r.args == f.argsType() {
+			return r, true
+		}
+	}
+
+	return nil, false
+}
+
+// Only one registration of the same type and name can be used to build each consumer.
+func (c *container) checkConsumers(key serviceKey, role string, r *containerRecord) error {
+	if role != contextual {
+		return nil
+	}
+
+	for _, registered := range c.constructors[containerKey{key, contextual}] {
+		for _, consumer := range r.consumers {
+			if slices.Contains(registered.consumers, consumer) {
+				return ErrDuplicateConstructor
+			}
+		}
+	}
+
+	return nil
+}
+
+// Returns registration the dependency of consumer is resolved with, if it is registered WhenBuilding consumer.
+func contextualOf(recordsMap map[containerKey][]*containerRecord, consumer *containerRecord, key serviceKey) (*containerRecord, bool) {
+	for _, r := range recordsMap[containerKey{key, contextual}] {
+		if slices.Contains(r.consumers, consumer.serviceType) {
+			return r, true
+		}
+	}
+
+	return nil, false
+}
+
+// Returns key of []T or map[string]T the group or map entries are collected into.
+func collectionKey(key containerKey) serviceKey {
+	if key.role == mapEntry {
+		return serviceKey{reflect.MapOf(stringType, key.serviceType), key.name, key.module}
+	}
+
+	return serviceKey{reflect.SliceOf(key.serviceType), key.name, key.module}
+}
+
+func checkMapKeys(key containerKey, records []*containerRecord) error {
+	if key.role != mapEntry {
+		return nil
+	}
+
+	keys := make(map[string]struct{}, len(records))
+	for _, r := range records {
+		if _, ok := keys[r.mapKey]; ok {
+			return newServiceBuilderError(
+				newDuplicateMapKeyError(r.mapKey),
+				r.lifetime,
+				collectionKey(key).String(),
+			)
+		}
+
+		keys[r.mapKey] = struct{}{}
+	}
+
+	return nil
+}
+
+// Binds record to interfaces from As options.
+// Aliases are stored as the same record under interface keys, so they share its instance and lifetime scope slot.
+func (c *container) addAliases(implementation reflect.Type, role string, conf RegistrationConfiguration, r *containerRecord) error {
+	keys := make([]serviceKey, 0, len(conf.Aliases))
+	for _, alias := range conf.Aliases {
+		switch {
+		case role != service:
+			return newAsError(ErrAsWithGroupOrMap, alias, implementation)
+		case alias.Kind() != reflect.Interface:
+			return newAsError(ErrAsWrongType, alias, implementation)
+		case !implementation.Implements(alias):
+			return newAsError(ErrAsNotImplemented, alias, implementation)
+		}
+
+		key := serviceKey{serviceType: alias, name: r.name}
+		if _, ok := c.constructors[containerKey{key, service}]; ok || key == r.key() || slices.Contains(keys, key) {
+			return newAsError(ErrDuplicateConstructor, alias, implementation)
+		}
+
+		keys = append(keys, key)
+	}
+
+	for _, key := range keys {
+		c.constructors[containerKey{key, service}] = []*containerRecord{r}
+	}
+
+	return nil
+}
+
+// Registers result object returned by constructor and each of its public fields as a separate service.
+// Field services depend on result object, so they share its lifetime, cleanup and dependencies.
+func (c *container) addResultObject(
+	lifetime Lifetime,
+	role string,
+	constructor any,
+	cType constructorType,
+	conf RegistrationConfiguration,
+) Container {
+	t := reflect.TypeOf(constructor)
+
+	if role != service || len(conf.Aliases) > 0 {
+		c.err.Store(newBadConstructorError(ErrResultObjectOption, t))
+		return c
+	}
+
+	fields, err := resultFields(t.Out(0))
+	if err != nil {
+		c.err.Store(newBadConstructorError(err, t))
+		return c
+	}
+
+	c.constructorsRWM.Lock()
+	defer c.constructorsRWM.Unlock()
+
+	key := serviceKey{serviceType: t.Out(0), name: conf.Name}
+	r := &containerRecord{
+		record: record{
+			constructorType: cType,
+			lifetime:        lifetime,
+			constructor:     constructor,
+			serviceType:     key.serviceType,
+			name:            key.name,
+			variadic:        t.IsVariadic(),
+		},
+	}
+
+	if err := fillDependencies(lifetime, t, t.NumIn(), r); err != nil {
+		c.err.Store(err)
+		return c
+	}
+
+	if err := nameDependencies(service, conf, r); err != nil {
+		c.err.Store(newBadConstructorError(err, t))
+		return c
+	}
+
+	keys := []containerKey{{key, service}}
+	records := []*containerRecord{r}
+
+	for _, field := range fields {
+		fieldKey := containerKey{serviceKey{serviceType: field.serviceType, name: field.name}, service}
+		if field.group {
+			fieldKey.role = group
+		}
+
+		if fieldKey.name == "" {
+			fieldKey.name = conf.Name
+		}
+
+		keys = append(keys, fieldKey)
+		records = append(records, &containerRecord{
+			record: record{
+				constructorType: onlyService,
+				lifetime:        lifetime,
+				constructor:     newResultFieldConstructor(key.serviceType, field),
+				serviceType:     fieldKey.serviceType,
+				name:            fieldKey.name,
+				resultField:     true,
+			},
+			dependencies: []serviceKey{key},
+			resultObject: r,
+		})
+	}
+
+	for i, k := range keys {
+		_, registered := c.constructors[k]
+		if k.role == service && (registered || slices.Contains(keys[:i], k)) {
+			c.err.Store(newBadConstructorError(ErrDuplicateConstructor, t))
+			return c
+		}
+	}
+
+	for i, k := range keys {
+		c.constructors[k] = append(c.constructors[k], records[i])
+	}
+
+	return c
+}
+
+func newResultFieldConstructor(resultObject reflect.Type, field resultField) any {
+	return reflect.MakeFunc(
+		reflect.FuncOf([]reflect.Type{resultObject}, []reflect.Type{field.serviceType}, false),
+		func(args []reflect.Value) []reflect.Value {
+			return []reflect.Value{args[0].Field(field.index)}
+		},
+	).Interface()
+}
+
+func (c *container) addPropertyFiller(
+	lifetime Lifetime,
+	role string,
+	construct func() (propertyFiller, error),
+	conf RegistrationConfiguration,
+) Container {
+	constructor, err := construct()
+	if err != nil {
+		c.err.Store(err)
+
+		return c
+	}
+
+	key := serviceKey{serviceType: constructor.Type, name: conf.Name}
+	r := &containerRecord{
+		record: record{
+			constructorType: withError,
+			serviceType:     key.serviceType,
+			name:            key.name,
+			lifetime:        lifetime,
+			constructor:     constructor.NewInstance,
+			consumers:       conf.Consumers,
+			innermost:       conf.InnermostScope,
+		},
+		mapKey: conf.MapKey,
+	}
+
+	for i, dep := range constructor.Dependencies {
+		r.addDependency(dep, constructor.Names[i], constructor.Optional[i])
+	}
+
+	if err := nameDependencies(role, conf, r); err != nil {
+		c.err.Store(newBadConstructorError(err, key.serviceType))
+
+		return c
+	}
+
+	switch role {
+	case service:
+		c.constructorsRWM.Lock()
+		defer c.constructorsRWM.Unlock()
+
+		if _, ok := c.constructors[containerKey{key, service}]; ok {
+			c.err.Store(newBadConstructorError(ErrDuplicateConstructor, key.serviceType))
+
+			return c
+		}
+
+		if err := c.addAliases(constructor.Implementation, role, conf, r); err != nil {
+			c.err.Store(err)
+
+			return c
+		}
+
+		c.constructors[containerKey{key, service}] = []*containerRecord{r}
+	case group, mapEntry:
+		c.constructorsRWM.Lock()
+		defer c.constructorsRWM.Unlock()
+
+		if err := c.addAliases(constructor.Implementation, role, conf, r); err != nil {
+			c.err.Store(err)
+
+			return c
+		}
+
+		c.constructors[containerKey{key, role}] = append(c.constructors[containerKey{key, role}], r)
+	case contextual:
+		c.constructorsRWM.Lock()
+		defer c.constructorsRWM.Unlock()
+
+		if err := c.checkConsumers(key, role, r); err != nil {
+			c.err.Store(newBadConstructorError(err, key.serviceType))
+
+			return c
+		}
+
+		c.constructors[containerKey{key, contextual}] = append(c.constructors[containerKey{key, contextual}], r)
+	case decorator:
+		if !slices.Contains(r.dependencies, key) {
+			c.err.Store(newBadConstructorError(ErrDecoratorBadDependency, key.serviceType))
+
+			return c
+		}
+
+		c.constructorsRWM.Lock()
+		defer c.constructorsRWM.Unlock()
+
+		c.constructors[containerKey{key, decorator}] = append(c.constructors[containerKey{key, decorator}], r)
+	}
+
+	return c
+}
+
+// Value is returned by constructor same as any other Singleton,
+// so it takes part in dependency analysis, decoration and Singleton cleanup.
+func newValueConstructor(value any, t reflect.Type, cleanup Cleanup) (any, constructorType) {
+	v := reflect.ValueOf(value)
+
+	if cleanup == nil {
+		constructor := reflect.MakeFunc(
+			reflect.FuncOf(nil, []reflect.Type{t}, false),
+			func([]reflect.Value) []reflect.Value { return []reflect.Value{v} },
+		)
+
+		return constructor.Interface(), onlyService
+	}
+
+	cleanupV := reflect.ValueOf(func() { cleanup() })
+	constructor := reflect.MakeFunc(
+		reflect.FuncOf(nil, []reflect.Type{t, cleanUpType, errorInterface}, false),
+		func([]reflect.Value) []reflect.Value {
+			return []reflect.Value{v, cleanupV, reflect.Zero(errorInterface)}
+		},
+	)
+
+	return constructor.Interface(), withErrorAndCleanUp
+}
+
+func getConstructorType(lifetime Lifetime, t reflect.Type) (constructorType, error) {
+	// Regular constructor
+	cType := onlyService
+
+	if t.Kind() != reflect.Func {
+		return cType, newConstructorUnsupportedError(t, lifetime)
+	}
+
+	numIn := t.NumIn()
+
+	// Singleton cannot be based on any context, but PerContext and Transient can
+	if lifetime == Singleton && numIn > 0 && t.In(0).Implements(contextInterface) {
+		return cType, newConstructorUnsupportedError(t, lifetime)
+	}
+
+	switch t.NumOut() {
+	case 1:
 		if out := t.Out(0); out.Implements(errorInterface) {
 			return cType, newConstructorUnsupportedError(t, lifetime)
 		}
@@ -454,8 +1670,10 @@ func getConstructorType(lifetime Lifetime, t reflect.Type) (constructorType, err
 	return cType, nil
 }
 
-func fillDependencies(lifetime Lifetime, t reflect.Type, r *containerRecord) error {
-	numIn := t.NumIn()
+// Fills dependencies from first numIn parameters of constructor.
+// Public fields of parameter objects are filled as separate dependencies.
+func fillDependencies(lifetime Lifetime, t reflect.Type, numIn int, r *containerRecord) error {
+	objects := make(map[int]structFields)
 	for i := 0; i < numIn; i++ {
 		argT := t.In(i)
 		if i > 0 && argT.Implements(contextInterface) {
@@ -463,72 +1681,320 @@ func fillDependencies(lifetime Lifetime, t reflect.Type, r *containerRecord) err
 		}
 
 		if argT.Implements(contextInterface) {
-			r.dependencies = append(r.dependencies, contextDepName)
+			r.dependencies = append(r.dependencies, serviceKey{serviceType: contextInterface})
 			r.dependsOnContext = true
 
 			continue
 		}
 
-		r.dependencies = append(r.dependencies, argT.String())
+		if isParameterObject(argT) {
+			fields, err := publicFields(argT)
+			if err != nil {
+				return newBadConstructorError(err, t)
+			}
+
+			for j, dep := range fields.dependencies {
+				r.addDependency(dep, fields.names[j], fields.optional[j])
+			}
+
+			objects[i] = fields
+
+			continue
+		}
+
+		r.addDependency(argT, "", false)
+	}
+
+	if len(objects) > 0 {
+		r.constructor = withParameterObjects(r.constructor, objects)
+	}
+
+	return nil
+}
+
+// Optional[T], Lazy[T] and Provider[T] dependencies are resolved by service of type T,
+// Factory[Args, T] is resolved by factory constructor.
+func (r *containerRecord) addDependency(t reflect.Type, name string, optional bool) {
+	serviceType := t
+	switch {
+	case t.Implements(optionalInterface):
+		serviceType = reflect.Zero(t).Interface().(optionalDependency).serviceType()
+		optional = true
+	case t.Implements(lazyInterface), t.Implements(factoryInterface):
+		if t.Implements(lazyInterface) {
+			serviceType = reflect.Zero(t).Interface().(lazyDependency).serviceType()
+		}
+
+		if r.lazy == nil {
+			r.lazy = make(map[int]reflect.Type)
+		}
+
+		r.lazy[len(r.dependencies)] = t
+	}
+
+	if optional {
+		if r.optional == nil {
+			r.optional = make(map[int]reflect.Type)
+		}
+
+		r.optional[len(r.dependencies)] = t
+	}
+
+	r.dependencies = append(r.dependencies, serviceKey{serviceType: serviceType, name: name})
+}
+
+// Decorators depend on the service with the same name by default,
+// all other dependencies are unnamed unless WithNamedDependency or field tag says otherwise.
+// Dependencies named by field tags keep their names.
+func nameDependencies(role string, conf RegistrationConfiguration, r *containerRecord) error {
+	for t, names := range conf.NamedDependencies {
+		position := 0
+
+		for i, dep := range r.dependencies {
+			if dep.serviceType != t || dep.name != "" {
+				continue
+			}
+
+			switch {
+			case len(names) == 1:
+				r.dependencies[i].name = names[0]
+			case position < len(names):
+				r.dependencies[i].name = names[position]
+			}
+
+			position++
+		}
+
+		if position == 0 || position < len(names) {
+			return ErrNamedDependencyNotUsed
+		}
+	}
+
+	if role != decorator {
+		return nil
+	}
+
+	for i, dep := range r.dependencies {
+		if _, ok := conf.NamedDependencies[dep.serviceType]; !ok && dep.serviceType == r.serviceType && dep.name == "" {
+			r.dependencies[i].name = r.name
+		}
 	}
 
 	return nil
 }
 
-func containerRecordsToLocatorRecords(recordsMap map[[2]string][]*containerRecord) map[string]*locatorRecord {
-	result := make(map[string]*locatorRecord)
+func containerRecordsToLocatorRecords(
+	recordsMap map[containerKey][]*containerRecord,
+	inferred map[serviceKey]*containerRecord,
+) (map[serviceKey]*locatorRecord, []*locatorRecord) {
+	result := make(map[serviceKey]*locatorRecord)
+	built := make(map[*containerRecord]*locatorRecord)
+	all := make([]*locatorRecord, 0, len(recordsMap))
+
+	// same container record registered under several keys (aliases) gets a single locator record
+	build := func(value *containerRecord) *locatorRecord {
+		if rec, ok := built[value]; ok {
+			return rec
+		}
+
+		rec := &locatorRecord{record: value.record}
+		built[value] = rec
+		all = append(all, rec)
+
+		return rec
+	}
 
 	for key, records := range recordsMap {
-		if key[1] != service {
-			continue
+		switch key.role {
+		case service:
+			for _, value := range records {
+				result[key.serviceKey] = build(value)
+			}
+		case factory, contextual:
+			for _, value := range records {
+				build(value)
+			}
+		case group:
+			groupRecords := make([]*locatorRecord, len(records))
+			for i, value := range records {
+				groupRecords[i] = build(value)
+			}
+
+			groupRecord := newGroupRecord(key.serviceKey, groupRecords)
+			result[collectionKey(key)] = groupRecord
+			all = append(all, groupRecord)
+		case mapEntry:
+			entryRecords := make([]*locatorRecord, len(records))
+			entryKeys := make([]string, len(records))
+			for i, value := range records {
+				entryRecords[i] = build(value)
+				entryKeys[i] = value.mapKey
+			}
+
+			mapRecord := newMapRecord(key.serviceKey, entryKeys, entryRecords)
+			result[collectionKey(key)] = mapRecord
+			all = append(all, mapRecord)
 		}
+	}
+
+	// inferred bindings are resolved same as aliases
+	for key, value := range inferred {
+		result[key] = build(value)
+	}
 
-		for _, value := range records {
-			result[key[0]] = &locatorRecord{record: value.record}
+	// Factory[Args, T] dependencies are resolved with factory constructors
+	for value := range built {
+		for _, dep := range value.dependencies {
+			if f, ok := factoryOf(recordsMap, dep); ok {
+				result[dep] = built[f]
+			}
 		}
 	}
 
+	for value, rec := range built {
+		rec.dependencies = toLocatorDependencies(value.dependencies, result)
+		rec.dependencyKeys = value.dependencies
+		useContextual(recordsMap, value, rec, built)
+	}
+
+	// aliases and inferred bindings are keyed by interface, but bound to the record of service
+	aliases := make(map[serviceKey]serviceKey)
 	for key, records := range recordsMap {
-		if key[1] != service {
-			continue
+		if key.role == service && key.serviceKey != records[0].key() {
+			aliases[key.serviceKey] = records[0].key()
 		}
+	}
 
-		for _, value := range records {
-			deps := make([]*locatorRecord, len(value.dependencies))
-			for i, dep := range value.dependencies {
-				if dep == contextDepName {
-					deps[i] = &locatorRecord{record: record{typeName: dep, id: -1}}
-					continue
-				}
+	for key, value := range inferred {
+		aliases[key] = value.key()
+	}
 
-				deps[i] = result[dep]
+	decorate := func(decorateAliases bool) {
+		for key, records := range recordsMap {
+			if _, isAlias := aliases[key.serviceKey]; key.role != decorator || isAlias != decorateAliases {
+				continue
 			}
 
-			result[key[0]].dependencies = deps
+			for _, value := range records {
+				deps := toLocatorDependencies(value.dependencies, result)
+
+				result[key.serviceKey] = &locatorRecord{record: value.record, dependencies: deps, dependencyKeys: value.dependencies}
+				useContextual(recordsMap, value, result[key.serviceKey], built)
+				all = append(all, result[key.serviceKey])
+			}
 		}
 	}
 
-	for key, records := range recordsMap {
-		if key[1] != decorator {
-			continue
+	// aliases share decorated instance of service, and decorators of aliases decorate it further
+	decorate(false)
+	for alias, key := range aliases {
+		result[alias] = result[key]
+	}
+	decorate(true)
+
+	return result, all
+}
+
+// Dependencies registered WhenBuilding consumer are linked instead of services of the same type and name,
+// so Get does not need to choose between them.
+func useContextual(
+	recordsMap map[containerKey][]*containerRecord,
+	consumer *containerRecord,
+	rec *locatorRecord,
+	built map[*containerRecord]*locatorRecord,
+) {
+	for i, dep := range consumer.dependencies {
+		if r, ok := contextualOf(recordsMap, consumer, dep); ok {
+			rec.dependencies[i] = built[r]
 		}
+	}
+}
 
-		for i, value := range records {
-			deps := make([]*locatorRecord, len(value.dependencies))
-			for i, dep := range value.dependencies {
-				if dep == contextDepName {
-					deps[i] = &locatorRecord{record: record{typeName: dep, id: -1}}
-					continue
-				}
+// Group record collects its members into []T.
+// It has the shortest lifetime among its members, so each member is still resolved with its own lifetime.
+func newGroupRecord(key serviceKey, members []*locatorRecord) *locatorRecord {
+	sliceType := reflect.SliceOf(key.serviceType)
+	in := make([]reflect.Type, len(members))
+	lifetime := Singleton
+
+	for i, member := range members {
+		in[i] = key.serviceType
+		lifetime = min(lifetime, member.lifetime)
+	}
+
+	constructor := reflect.MakeFunc(
+		reflect.FuncOf(in, []reflect.Type{sliceType}, false),
+		func(args []reflect.Value) []reflect.Value {
+			services := reflect.MakeSlice(sliceType, 0, len(args))
+
+			return []reflect.Value{reflect.Append(services, args...)}
+		},
+	)
+
+	return &locatorRecord{
+		record: record{
+			constructor:     constructor.Interface(),
+			serviceType:     sliceType,
+			name:            key.name,
+			module:          key.module,
+			private:         key.module != "",
+			constructorType: onlyService,
+			lifetime:        lifetime,
+			innermost:       slices.ContainsFunc(members, func(r *locatorRecord) bool { return r.innermost }),
+		},
+		dependencies: members,
+	}
+}
+
+// Map record collects its entries into map[string]T.
+// Same as group record it has the shortest lifetime among its entries.
+func newMapRecord(key serviceKey, keys []string, entries []*locatorRecord) *locatorRecord {
+	mapType := reflect.MapOf(stringType, key.serviceType)
+	in := make([]reflect.Type, len(entries))
+	lifetime := Singleton
 
-				deps[i] = result[dep]
+	for i, entry := range entries {
+		in[i] = key.serviceType
+		lifetime = min(lifetime, entry.lifetime)
+	}
+
+	constructor := reflect.MakeFunc(
+		reflect.FuncOf(in, []reflect.Type{mapType}, false),
+		func(args []reflect.Value) []reflect.Value {
+			services := reflect.MakeMapWithSize(mapType, len(args))
+			for i, arg := range args {
+				services.SetMapIndex(reflect.ValueOf(keys[i]), arg)
 			}
 
-			decorated := result[key[0]]
-			result[key[0]] = &locatorRecord{record: value.record, dependencies: deps}
-			result[fmt.Sprintf("decorated::%s::%d", key[0], i)] = decorated
+			return []reflect.Value{services}
+		},
+	)
+
+	return &locatorRecord{
+		record: record{
+			constructor:     constructor.Interface(),
+			serviceType:     mapType,
+			name:            key.name,
+			module:          key.module,
+			private:         key.module != "",
+			constructorType: onlyService,
+			lifetime:        lifetime,
+			innermost:       slices.ContainsFunc(entries, func(r *locatorRecord) bool { return r.innermost }),
+		},
+		dependencies: entries,
+	}
+}
+
+func toLocatorDependencies(dependencies []serviceKey, records map[serviceKey]*locatorRecord) []*locatorRecord {
+	deps := make([]*locatorRecord, len(dependencies))
+	for i, dep := range dependencies {
+		if dep.serviceType == contextInterface {
+			deps[i] = &locatorRecord{record: record{serviceType: dep.serviceType, id: -1}}
+			continue
 		}
+
+		deps[i] = records[dep]
 	}
 
-	return result
+	return deps
 }
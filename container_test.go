@@ -4,8 +4,11 @@ import (
 	"bytes"
 	"context"
 	"errors"
+	htmltemplate "html/template"
 	"log/slog"
+	"reflect"
 	"sync"
+	texttemplate "text/template"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -98,6 +101,136 @@ var _ = Describe("Container", func() {
 			Expect(err).ShouldNot(HaveOccurred())
 		})
 
+		It("should allow add services of the same type with different names", func() {
+			_, err := tinysl.
+				Add(tinysl.Singleton, nameServiceConstructor).
+				Add(tinysl.Singleton, nameServiceConstructor, tinysl.WithName("replica")).
+				ServiceLocator()
+			Expect(err).ShouldNot(HaveOccurred())
+		})
+
+		It("should not allow add duplicate services with the same name", func() {
+			_, err := tinysl.
+				Add(tinysl.Singleton, nameServiceConstructor, tinysl.WithName("replica")).
+				Add(tinysl.Singleton, nameServiceConstructor, tinysl.WithName("replica")).
+				ServiceLocator()
+
+			Expect(err).Should(HaveOccurred())
+			Expect(err).Should(BeAssignableToTypeOf(new(tinysl.BadConstructorError)))
+			Expect(errors.Unwrap(err)).Should(MatchError(tinysl.ErrDuplicateConstructor))
+		})
+
+		It("should return error for missing named dependency", func() {
+			_, err := tinysl.
+				Add(tinysl.Singleton, nameServiceConstructor).
+				Add(tinysl.Singleton, heroConstructor, tinysl.WithNamedDependency[NameService]("replica")).
+				ServiceLocator()
+
+			Expect(err).Should(HaveOccurred())
+			Expect(err).Should(BeAssignableToTypeOf(new(tinysl.ServiceBuilderError)))
+			Expect(errors.Unwrap(err)).Should(BeAssignableToTypeOf(new(tinysl.ConstructorNotFoundError)))
+			Expect(err.Error()).To(ContainSubstring(`tinysl_test.NameService named "replica" constructor not found`))
+		})
+
+		It("should return error if named dependency is not used by constructor", func() {
+			_, err := tinysl.
+				Add(tinysl.Singleton, nameServiceConstructor).
+				Add(tinysl.Singleton, heroConstructor, tinysl.WithNamedDependency[*Impostor]("replica")).
+				ServiceLocator()
+
+			Expect(err).Should(HaveOccurred())
+			Expect(err).Should(BeAssignableToTypeOf(new(tinysl.BadConstructorError)))
+			Expect(errors.Unwrap(err)).Should(MatchError(tinysl.ErrNamedDependencyNotUsed))
+		})
+
+		It("should return error if constructor takes less dependencies than names", func() {
+			_, err := tinysl.
+				Add(tinysl.Singleton, nameServiceConstructor).
+				Add(tinysl.Singleton, heroConstructor,
+					tinysl.WithNamedDependency[NameService]("primary"),
+					tinysl.WithNamedDependency[NameService]("replica"),
+				).
+				ServiceLocator()
+
+			Expect(err).Should(HaveOccurred())
+			Expect(err).Should(BeAssignableToTypeOf(new(tinysl.BadConstructorError)))
+			Expect(errors.Unwrap(err)).Should(MatchError(tinysl.ErrNamedDependencyNotUsed))
+		})
+
+		It("should return error for circular dependencies between named services", func() {
+			_, err := tinysl.
+				Add(tinysl.Transient, nameServiceConstructor).
+				Add(tinysl.Transient, impostorConstructor, tinysl.WithNamedDependency[*Hero]("disguised")).
+				Add(tinysl.Transient, disguisedImpostorConstructor, tinysl.WithName("disguised")).
+				ServiceLocator()
+
+			Expect(err).Should(HaveOccurred())
+			Expect(err).Should(BeAssignableToTypeOf(new(tinysl.ServiceBuilderError)))
+			Expect(errors.Unwrap(err)).Should(BeAssignableToTypeOf(new(tinysl.CircularDependencyError)))
+		})
+
+		It("should return error if named dependency does not respect lifetime hierarchy", func() {
+			_, err := tinysl.
+				Add(tinysl.Singleton, nameServiceConstructor).
+				Add(tinysl.PerContext, nameServiceConstructor, tinysl.WithName("request")).
+				Add(tinysl.Singleton, heroConstructor, tinysl.WithNamedDependency[NameService]("request")).
+				ServiceLocator()
+
+			Expect(err).Should(HaveOccurred())
+			Expect(err).Should(BeAssignableToTypeOf(new(tinysl.ServiceBuilderError)))
+			Expect(errors.Unwrap(err)).Should(BeAssignableToTypeOf(new(tinysl.ScopeHierarchyError)))
+		})
+
+		It("should register map entries", func() {
+			_, err := tinysl.
+				Add(tinysl.Singleton, nameServiceConstructor, tinysl.WithMapKey("bob")).
+				Add(tinysl.Singleton, func() NameService { return NameProvider("Sam") }, tinysl.WithMapKey("sam")).
+				Add(tinysl.Singleton, func(names map[string]NameService) *Hero { return &Hero{} }).
+				ServiceLocator()
+			Expect(err).ShouldNot(HaveOccurred())
+		})
+
+		It("should return error for duplicate map keys", func() {
+			_, err := tinysl.
+				Add(tinysl.Singleton, nameServiceConstructor, tinysl.WithMapKey("bob")).
+				Add(tinysl.Singleton, func() NameService { return NameProvider("Sam") }, tinysl.WithMapKey("bob")).
+				ServiceLocator()
+
+			Expect(err).Should(HaveOccurred())
+			Expect(err).Should(BeAssignableToTypeOf(new(tinysl.ServiceBuilderError)))
+			Expect(errors.Unwrap(err)).Should(BeAssignableToTypeOf(new(tinysl.DuplicateMapKeyError)))
+		})
+
+		It("should return error if map entry does not respect lifetime hierarchy", func() {
+			_, err := tinysl.
+				Add(tinysl.Singleton, nameServiceConstructor, tinysl.WithMapKey("bob")).
+				Add(tinysl.PerContext, func() NameService { return NameProvider("Sam") }, tinysl.WithMapKey("sam")).
+				Add(tinysl.Singleton, func(names map[string]NameService) *Hero { return &Hero{} }).
+				ServiceLocator()
+
+			Expect(err).Should(HaveOccurred())
+			Expect(err).Should(BeAssignableToTypeOf(new(tinysl.ServiceBuilderError)))
+			Expect(errors.Unwrap(err)).Should(BeAssignableToTypeOf(new(tinysl.ScopeHierarchyError)))
+		})
+
+		It("should return error if constructor is both group member and map entry", func() {
+			_, err := tinysl.
+				Add(tinysl.Singleton, nameServiceConstructor, tinysl.WithMapKey("bob"), tinysl.AsGroupMember).
+				ServiceLocator()
+
+			Expect(err).Should(HaveOccurred())
+			Expect(err).Should(BeAssignableToTypeOf(new(tinysl.BadConstructorError)))
+			Expect(errors.Unwrap(err)).Should(MatchError(tinysl.ErrGroupMemberAndMapEntry))
+		})
+
+		It("should distinguish types with the same name from different packages", func() {
+			_, err := tinysl.
+				Add(tinysl.Singleton, func() *texttemplate.Template { return texttemplate.New("text") }).
+				Add(tinysl.Singleton, func() *htmltemplate.Template { return htmltemplate.New("html") }).
+				ServiceLocator()
+			Expect(err).ShouldNot(HaveOccurred())
+		})
+
 		It("should refuse register variadic constructors", func() {
 			variadicConstructor := func(args ...any) (NameService, error) {
 				return NameProvider("Bob"), nil
@@ -107,251 +240,1141 @@ var _ = Describe("Container", func() {
 				ServiceLocator()
 
 			Expect(err).Should(HaveOccurred())
-			Expect(err).Should(BeAssignableToTypeOf(new(tinysl.BadConstructorError)))
-			Expect(errors.Unwrap(err)).Should(MatchError(tinysl.ErrVariadicConstructor))
+			Expect(err).Should(BeAssignableToTypeOf(new(tinysl.BadConstructorError)))
+			Expect(errors.Unwrap(err)).Should(MatchError(tinysl.ErrVariadicConstructor))
+		})
+
+		It("should register group members", func() {
+			_, err := tinysl.
+				New(tinysl.SilenceUseSingletonWarnings).
+				Add(tinysl.Singleton, nameServiceConstructor, tinysl.AsGroupMember).
+				Add(tinysl.PerContext, nameServiceConstructor, tinysl.AsGroupMember).
+				Add(tinysl.PerContext, func(names ...NameService) *Hero { return &Hero{} }).
+				ServiceLocator()
+			Expect(err).ShouldNot(HaveOccurred())
+		})
+
+		It("should return error if group member does not respect lifetime hierarchy", func() {
+			_, err := tinysl.
+				Add(tinysl.Singleton, nameServiceConstructor, tinysl.AsGroupMember).
+				Add(tinysl.PerContext, nameServiceConstructor, tinysl.AsGroupMember).
+				Add(tinysl.Singleton, func(names []NameService) *Hero { return &Hero{} }).
+				ServiceLocator()
+
+			Expect(err).Should(HaveOccurred())
+			Expect(err).Should(BeAssignableToTypeOf(new(tinysl.ServiceBuilderError)))
+			Expect(errors.Unwrap(err)).Should(BeAssignableToTypeOf(new(tinysl.ScopeHierarchyError)))
+		})
+
+		It("should return error if group member depends on its group", func() {
+			_, err := tinysl.
+				Add(tinysl.Transient, nameServiceConstructor, tinysl.AsGroupMember).
+				Add(tinysl.Transient, func(names []NameService) NameService { return names[0] }, tinysl.AsGroupMember).
+				ServiceLocator()
+
+			Expect(err).Should(HaveOccurred())
+			Expect(err).Should(BeAssignableToTypeOf(new(tinysl.ServiceBuilderError)))
+			Expect(errors.Unwrap(err)).Should(BeAssignableToTypeOf(new(tinysl.CircularDependencyError)))
+		})
+
+		It("should return error if group type is registered as a service", func() {
+			_, err := tinysl.
+				Add(tinysl.Singleton, nameServiceConstructor, tinysl.AsGroupMember).
+				Add(tinysl.Singleton, func() []NameService { return nil }).
+				ServiceLocator()
+
+			Expect(err).Should(HaveOccurred())
+			Expect(err).Should(BeAssignableToTypeOf(new(tinysl.ServiceBuilderError)))
+			Expect(errors.Unwrap(err)).Should(MatchError(tinysl.ErrDuplicateConstructor))
+		})
+
+		It("should be tread-safe", func() {
+			sl := tinysl.New(tinysl.SilenceUseSingletonWarnings)
+
+			var wg sync.WaitGroup
+
+			wg.Add(1)
+			go func() {
+				defer GinkgoRecover()
+
+				_ = sl.Add(tinysl.PerContext, nameServiceConstructor)
+
+				wg.Done()
+			}()
+
+			wg.Add(1)
+			go func() {
+				defer GinkgoRecover()
+
+				_ = sl.Add(tinysl.PerContext, nameServiceConstructor)
+
+				wg.Done()
+			}()
+
+			wg.Wait()
+
+			_, err := sl.ServiceLocator()
+			Expect(err).Should(HaveOccurred())
+		})
+
+		It("should be tread-safe adding default registrations", func() {
+			sl := tinysl.New(tinysl.SilenceUseSingletonWarnings)
+
+			var wg sync.WaitGroup
+
+			wg.Add(1)
+			go func() {
+				defer GinkgoRecover()
+
+				_ = sl.Add(tinysl.PerContext, nameServiceConstructor, tinysl.AsDefault)
+
+				wg.Done()
+			}()
+
+			wg.Add(1)
+			go func() {
+				defer GinkgoRecover()
+
+				_, _ = sl.ServiceLocator()
+
+				wg.Done()
+			}()
+
+			wg.Wait()
+
+			_, err := sl.ServiceLocator()
+			Expect(err).ShouldNot(HaveOccurred())
+		})
+
+		It("should return error for circular dependencies", func() {
+			_, err := tinysl.
+				Add(tinysl.Transient, nameServiceConstructor).
+				Add(tinysl.Transient, impostorConstructor).
+				Add(tinysl.Transient, disguisedImpostorConstructor).
+				ServiceLocator()
+
+			Expect(err).Should(HaveOccurred())
+			Expect(err).Should(BeAssignableToTypeOf(new(tinysl.ServiceBuilderError)))
+			Expect(errors.Unwrap(err)).Should(BeAssignableToTypeOf(new(tinysl.CircularDependencyError)))
+		})
+
+		It("should return error for missing dependency", func() {
+			_, err := tinysl.
+				Add(tinysl.Transient, nameServiceConstructor).
+				Add(tinysl.Transient, tableTimerConstructor).
+				Add(tinysl.Transient, impostorConstructor).
+				ServiceLocator()
+
+			Expect(err).Should(HaveOccurred())
+			Expect(err).Should(BeAssignableToTypeOf(new(tinysl.ServiceBuilderError)))
+			Expect(errors.Unwrap(err)).Should(BeAssignableToTypeOf(new(tinysl.ConstructorNotFoundError)))
+		})
+
+		It("should return error for unsupported lifetime", func() {
+			_, err := tinysl.
+				Add(4, nameServiceConstructor).
+				ServiceLocator()
+
+			Expect(err).Should(HaveOccurred())
+			Expect(err).Should(BeAssignableToTypeOf(tinysl.LifetimeUnsupportedError("")))
+		})
+
+		It("should return error for wrong constructor type", func() {
+			_, err := tinysl.
+				Add(tinysl.Transient, "just random human made mistake").
+				ServiceLocator()
+
+			Expect(err).Should(HaveOccurred())
+			Expect(err).Should(BeAssignableToTypeOf(new(tinysl.BadConstructorError)))
+			Expect(errors.Unwrap(err)).Should(BeAssignableToTypeOf(new(tinysl.ConstructorTemplateError)))
+		})
+
+		It("should return error for constructor returning wrong type", func() {
+			badConstructor1 := func() error {
+				return nil
+			}
+
+			badConstructor2 := func() (int, bool) {
+				return 0, false
+			}
+
+			badConstructor3 := func() (int, bool, error) {
+				return 0, false, nil
+			}
+
+			badConstructor4 := func() (int, tinysl.Cleanup, bool) {
+				return 0, func() {}, false
+			}
+
+			badConstructor5 := func() (int, error, tinysl.Cleanup) {
+				return 0, nil, func() {}
+			}
+
+			badConstructor6 := func() (int, bool, tinysl.Cleanup, error) {
+				return 0, false, func() {}, nil
+			}
+
+			_, err := tinysl.
+				Add(tinysl.Transient, badConstructor1).
+				ServiceLocator()
+
+			Expect(err).Should(HaveOccurred())
+			Expect(err).Should(BeAssignableToTypeOf(new(tinysl.BadConstructorError)))
+			Expect(errors.Unwrap(err)).Should(BeAssignableToTypeOf(new(tinysl.ConstructorTemplateError)))
+
+			_, err = tinysl.
+				Add(tinysl.Transient, badConstructor2).
+				ServiceLocator()
+
+			Expect(err).Should(HaveOccurred())
+			Expect(err).Should(BeAssignableToTypeOf(new(tinysl.BadConstructorError)))
+			Expect(errors.Unwrap(err)).Should(BeAssignableToTypeOf(new(tinysl.ConstructorTemplateError)))
+
+			_, err = tinysl.
+				Add(tinysl.Transient, badConstructor3).
+				ServiceLocator()
+
+			Expect(err).Should(HaveOccurred())
+			Expect(err).Should(BeAssignableToTypeOf(new(tinysl.BadConstructorError)))
+			Expect(errors.Unwrap(err)).Should(BeAssignableToTypeOf(new(tinysl.ConstructorTemplateError)))
+
+			_, err = tinysl.
+				Add(tinysl.Transient, badConstructor4).
+				ServiceLocator()
+
+			Expect(err).Should(HaveOccurred())
+			Expect(err).Should(BeAssignableToTypeOf(new(tinysl.BadConstructorError)))
+			Expect(errors.Unwrap(err)).Should(BeAssignableToTypeOf(new(tinysl.ConstructorTemplateError)))
+
+			_, err = tinysl.
+				Add(tinysl.Transient, badConstructor5).
+				ServiceLocator()
+
+			Expect(err).Should(HaveOccurred())
+			Expect(err).Should(BeAssignableToTypeOf(new(tinysl.BadConstructorError)))
+			Expect(errors.Unwrap(err)).Should(BeAssignableToTypeOf(new(tinysl.ConstructorTemplateError)))
+
+			_, err = tinysl.
+				Add(tinysl.Transient, badConstructor6).
+				ServiceLocator()
+
+			Expect(err).Should(HaveOccurred())
+			Expect(err).Should(BeAssignableToTypeOf(new(tinysl.BadConstructorError)))
+			Expect(errors.Unwrap(err)).Should(BeAssignableToTypeOf(new(tinysl.ConstructorTemplateError)))
+		})
+
+		It("should return error for constructor with context.Context not as a first argument", func() {
+			badConstructor := func(nameService NameService, ctx context.Context) (*Hero, error) {
+				return &Hero{name: nameService.Name()}, nil
+			}
+
+			_, err := tinysl.
+				Add(tinysl.Transient, nameServiceConstructor).
+				Add(tinysl.Transient, badConstructor).
+				ServiceLocator()
+
+			Expect(err).Should(HaveOccurred())
+			Expect(err).Should(BeAssignableToTypeOf(new(tinysl.BadConstructorError)))
+			Expect(errors.Unwrap(err)).Should(BeAssignableToTypeOf(new(tinysl.ConstructorTemplateError)))
+		})
+
+		It("should return error for constructor if dependency tree does not respect lifetime hierarchy", func() {
+			_, err := tinysl.
+				Add(tinysl.PerContext, tableTimerConstructor).
+				Add(tinysl.Transient, nameServiceConstructor).
+				ServiceLocator()
+
+			Expect(err).Should(HaveOccurred())
+			Expect(err).Should(BeAssignableToTypeOf(new(tinysl.ServiceBuilderError)))
+			Expect(errors.Unwrap(err)).Should(BeAssignableToTypeOf(new(tinysl.ScopeHierarchyError)))
+		})
+
+		It("should return error for constructor if service can be made Singleton", func() {
+			var buf bytes.Buffer
+
+			slog.SetDefault(
+				slog.New(
+					slog.NewTextHandler(
+						&buf,
+						&slog.HandlerOptions{
+							ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+								if a.Key == slog.TimeKey && len(groups) == 0 {
+									return slog.Attr{}
+								}
+								return a
+							},
+						},
+					),
+				),
+			)
+			_, err := tinysl.
+				Add(tinysl.PerContext, heroConstructor).
+				Add(tinysl.Singleton, nameServiceConstructor).
+				ServiceLocator()
+
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(buf.String()).To(Equal("level=INFO msg=\"ERROR your dependency hierarchy can be optimised error=\\\"PerContext *tinysl_test.Hero should be a Singleton\\\"\"\n"))
+			slog.SetDefault(slog.Default())
+		})
+
+		It("should ignore scope analyzer errors if said so", func() {
+			var buf bytes.Buffer
+
+			slog.SetDefault(
+				slog.New(
+					slog.NewTextHandler(
+						&buf,
+						&slog.HandlerOptions{
+							ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+								if a.Key == slog.TimeKey && len(groups) == 0 {
+									return slog.Attr{}
+								}
+								return a
+							},
+						},
+					),
+				),
+			)
+
+			_, err := tinysl.
+				New(tinysl.SilenceUseSingletonWarnings).
+				Add(tinysl.PerContext, tableTimerConstructor).
+				Add(tinysl.Transient, nameServiceConstructor).
+				ServiceLocator()
+
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(buf.String()).To(BeEmpty())
+			slog.SetDefault(slog.Default())
+		})
+
+		It("should return first encountered error", func() {
+			_, err := tinysl.
+				Add(tinysl.Transient, "just random human made mistake").
+				Add(4, nameServiceConstructor).
+				ServiceLocator()
+
+			Expect(err).Should(HaveOccurred())
+			Expect(err).Should(BeAssignableToTypeOf(new(tinysl.BadConstructorError)))
+			Expect(errors.Unwrap(err)).Should(BeAssignableToTypeOf(new(tinysl.ConstructorTemplateError)))
+		})
+	})
+	Context("AddValue", func() {
+		It("should register value", func() {
+			_, err := tinysl.
+				AddValue(NameProvider("Bob")).
+				Add(tinysl.Singleton, func(name NameProvider) *Hero { return &Hero{name.Name()} }).
+				ServiceLocator()
+			Expect(err).ShouldNot(HaveOccurred())
+		})
+
+		It("should not allow add duplicate value", func() {
+			_, err := tinysl.
+				AddValue(NameProvider("Bob")).
+				Add(tinysl.Singleton, nameProviderConstructor).
+				ServiceLocator()
+
+			Expect(err).Should(HaveOccurred())
+			Expect(err).Should(BeAssignableToTypeOf(new(tinysl.BadConstructorError)))
+			Expect(errors.Unwrap(err)).Should(MatchError(tinysl.ErrDuplicateConstructor))
+		})
+
+		It("should return error for nil value", func() {
+			_, err := tinysl.
+				AddValue(nil).
+				ServiceLocator()
+
+			Expect(err).Should(MatchError(tinysl.ErrNilValue))
+		})
+
+		It("should return error if cleanup is used with constructor", func() {
+			_, err := tinysl.
+				Add(tinysl.Singleton, nameProviderConstructor, tinysl.WithCleanup(func() {})).
+				ServiceLocator()
+
+			Expect(err).Should(HaveOccurred())
+			Expect(err).Should(BeAssignableToTypeOf(new(tinysl.BadConstructorError)))
+			Expect(errors.Unwrap(err)).Should(MatchError(tinysl.ErrCleanupWithoutValue))
+		})
+
+		It("should allow decorate value", func() {
+			_, err := tinysl.
+				AddValue(&Hero{"Bob"}).
+				Decorate(tinysl.Singleton, func(h *Hero) *Hero { return &Hero{"Mr. " + h.name} }).
+				ServiceLocator()
+			Expect(err).ShouldNot(HaveOccurred())
+		})
+	})
+
+	Context("As", func() {
+		It("should bind service to additional interfaces", func() {
+			_, err := tinysl.
+				Add(tinysl.Singleton, func() *Impostor { return &Impostor{name: "Bob"} },
+					tinysl.As[NameService](),
+					tinysl.As[Announcer](),
+				).
+				ServiceLocator()
+			Expect(err).ShouldNot(HaveOccurred())
+		})
+
+		It("should return error if As is not called with an interface", func() {
+			_, err := tinysl.
+				Add(tinysl.Singleton, func() *Impostor { return &Impostor{name: "Bob"} }, tinysl.As[*Hero]()).
+				ServiceLocator()
+
+			Expect(err).Should(HaveOccurred())
+			Expect(err).Should(BeAssignableToTypeOf(new(tinysl.AsError)))
+			Expect(errors.Unwrap(err)).Should(MatchError(tinysl.ErrAsWrongType))
+		})
+
+		It("should return error if service does not implement interface", func() {
+			_, err := tinysl.
+				Add(tinysl.Singleton, func() *Hero { return &Hero{"Bob"} }, tinysl.As[NameService]()).
+				ServiceLocator()
+
+			Expect(err).Should(HaveOccurred())
+			Expect(err).Should(BeAssignableToTypeOf(new(tinysl.AsError)))
+			Expect(errors.Unwrap(err)).Should(MatchError(tinysl.ErrAsNotImplemented))
+		})
+
+		It("should return error if interface was already added", func() {
+			_, err := tinysl.
+				Add(tinysl.Singleton, nameServiceConstructor).
+				Add(tinysl.Singleton, func() *Impostor { return &Impostor{name: "Bob"} }, tinysl.As[NameService]()).
+				ServiceLocator()
+
+			Expect(err).Should(HaveOccurred())
+			Expect(err).Should(BeAssignableToTypeOf(new(tinysl.AsError)))
+			Expect(errors.Unwrap(err)).Should(MatchError(tinysl.ErrDuplicateConstructor))
+		})
+
+		It("should return error if used with group member", func() {
+			_, err := tinysl.
+				Add(tinysl.Singleton, func() *Impostor { return &Impostor{name: "Bob"} },
+					tinysl.AsGroupMember,
+					tinysl.As[NameService](),
+				).
+				ServiceLocator()
+
+			Expect(err).Should(HaveOccurred())
+			Expect(err).Should(BeAssignableToTypeOf(new(tinysl.AsError)))
+			Expect(errors.Unwrap(err)).Should(MatchError(tinysl.ErrAsWithGroupOrMap))
+		})
+
+		It("should validate I implementation against additional interfaces", func() {
+			_, err := tinysl.
+				Add(tinysl.Singleton, nameServiceConstructor).
+				Add(tinysl.Singleton, tinysl.I[HelloService, ServiceWithPublicFields], tinysl.As[Announcer]()).
+				ServiceLocator()
+
+			Expect(err).Should(HaveOccurred())
+			Expect(err).Should(BeAssignableToTypeOf(new(tinysl.AsError)))
+			Expect(errors.Unwrap(err)).Should(MatchError(tinysl.ErrAsNotImplemented))
+		})
+
+		It("should return error for circular dependency through interface", func() {
+			_, err := tinysl.
+				Add(tinysl.Singleton, impostorConstructor, tinysl.As[Announcer]()).
+				Add(tinysl.Singleton, func(a Announcer) NameService { return NameProvider(a.Announce()) }).
+				Add(tinysl.Singleton, heroConstructor).
+				ServiceLocator()
+
+			Expect(err).Should(HaveOccurred())
+			Expect(err).Should(BeAssignableToTypeOf(new(tinysl.ServiceBuilderError)))
+			Expect(errors.Unwrap(err)).Should(BeAssignableToTypeOf(new(tinysl.CircularDependencyError)))
+		})
+	})
+
+	Context("Optional", func() {
+		It("should not require optional dependency to be registered", func() {
+			_, err := tinysl.
+				Add(tinysl.Singleton, func(nameService tinysl.Optional[NameService]) *Hero {
+					return &Hero{nameService.OrElse(NameProvider("Sam")).Name()}
+				}).
+				Add(tinysl.Singleton, tinysl.T[ServiceWithOptionalFields]).
+				ServiceLocator()
+
+			Expect(err).ShouldNot(HaveOccurred())
+		})
+
+		It("should check scope hierarchy of registered optional dependency", func() {
+			_, err := tinysl.
+				Add(tinysl.PerContext, nameServiceConstructor).
+				Add(tinysl.Singleton, func(nameService tinysl.Optional[NameService]) *Hero {
+					return &Hero{nameService.OrElse(NameProvider("Sam")).Name()}
+				}).
+				ServiceLocator()
+
+			Expect(err).Should(HaveOccurred())
+			Expect(errors.Unwrap(err)).Should(BeAssignableToTypeOf(new(tinysl.ScopeHierarchyError)))
+		})
+
+		It("should check scope hierarchy of registered optional field", func() {
+			_, err := tinysl.
+				Add(tinysl.PerContext, nameServiceConstructor).
+				Add(tinysl.Singleton, tinysl.T[ServiceWithOptionalFields]).
+				ServiceLocator()
+
+			Expect(err).Should(HaveOccurred())
+			Expect(errors.Unwrap(err)).Should(BeAssignableToTypeOf(new(tinysl.ScopeHierarchyError)))
+		})
+	})
+
+	Context("Lazy and Provider", func() {
+		It("should allow circular dependency through Lazy", func() {
+			_, err := tinysl.
+				Add(tinysl.Singleton, func(tinysl.Lazy[*Hero]) NameService { return NameProvider("Bob") }).
+				Add(tinysl.Singleton, heroConstructor).
+				ServiceLocator()
+
+			Expect(err).ShouldNot(HaveOccurred())
+		})
+
+		It("should allow Provider of shorter lifetime", func() {
+			_, err := tinysl.
+				Add(tinysl.PerContext, nameServiceConstructor).
+				Add(tinysl.Singleton, func(tinysl.Provider[NameService]) *Hero { return &Hero{"Bob"} }).
+				ServiceLocator()
+
+			Expect(err).ShouldNot(HaveOccurred())
+		})
+
+		It("should return error if lazy dependency is not registered", func() {
+			_, err := tinysl.
+				Add(tinysl.Singleton, func(tinysl.Provider[NameService]) *Hero { return &Hero{"Bob"} }).
+				ServiceLocator()
+
+			Expect(err).Should(HaveOccurred())
+			Expect(errors.Unwrap(err)).Should(BeAssignableToTypeOf(new(tinysl.ConstructorNotFoundError)))
+		})
+	})
+
+	Context("AddProvider", func() {
+		It("should register provider methods", func() {
+			_, err := tinysl.
+				AddProvider(HeroProviders{name: "Bob"}).
+				ServiceLocator()
+
+			Expect(err).ShouldNot(HaveOccurred())
+		})
+
+		It("should register provider methods with lifetimes chosen by provider", func() {
+			_, err := tinysl.
+				AddProvider(AnnotatedHeroProviders{}).
+				ServiceLocator()
+
+			Expect(err).ShouldNot(HaveOccurred())
+		})
+
+		It("should return error if provider method has unsupported signature", func() {
+			_, err := tinysl.
+				AddProvider(new(Impostor)).
+				ServiceLocator()
+
+			Expect(err).Should(HaveOccurred())
+			Expect(err).Should(BeAssignableToTypeOf(new(tinysl.ProviderError)))
+			Expect(err.(*tinysl.ProviderError).Method).To(Equal("Disguise"))
+			Expect(errors.Unwrap(errors.Unwrap(err))).Should(BeAssignableToTypeOf(new(tinysl.ConstructorTemplateError)))
+		})
+
+		It("should return error if lifetime is set for unknown method", func() {
+			_, err := tinysl.
+				AddProvider(lifetimesOf{"Hero": tinysl.PerContext}).
+				ServiceLocator()
+
+			Expect(err).Should(HaveOccurred())
+			Expect(errors.Unwrap(err)).Should(MatchError(tinysl.ErrProviderUnknownMethod))
+		})
+
+		It("should return error if provider has no methods", func() {
+			_, err := tinysl.
+				AddProvider(struct{}{}).
+				ServiceLocator()
+
+			Expect(err).Should(HaveOccurred())
+			Expect(errors.Unwrap(err)).Should(MatchError(tinysl.ErrProviderWithoutMethods))
+
+			_, err = tinysl.
+				AddProvider((*HeroProviders)(nil)).
+				ServiceLocator()
+
+			Expect(err).Should(HaveOccurred())
+			Expect(errors.Unwrap(err)).Should(MatchError(tinysl.ErrNilValue))
+		})
+	})
+
+	Context("AsDefault", func() {
+		It("should not report duplicate for regular registration of default type", func() {
+			_, err := tinysl.
+				Add(tinysl.Singleton, nameServiceConstructor, tinysl.AsDefault).
+				Add(tinysl.Singleton, nameServiceConstructor).
+				AddValue(NameProvider("Bob"), tinysl.AsDefault).
+				Add(tinysl.Singleton, heroConstructor).
+				ServiceLocator()
+
+			Expect(err).ShouldNot(HaveOccurred())
+		})
+
+		It("should return error if default registration is duplicated", func() {
+			_, err := tinysl.
+				Add(tinysl.Singleton, nameServiceConstructor, tinysl.AsDefault).
+				Add(tinysl.Singleton, nameServiceConstructor, tinysl.AsDefault).
+				ServiceLocator()
+
+			Expect(err).Should(HaveOccurred())
+			Expect(errors.Unwrap(err)).Should(MatchError(tinysl.ErrDuplicateConstructor))
+		})
+
+		It("should return error if default registration dependency is not registered", func() {
+			_, err := tinysl.
+				Add(tinysl.Singleton, heroConstructor, tinysl.AsDefault).
+				ServiceLocator()
+
+			Expect(err).Should(HaveOccurred())
+			Expect(errors.Unwrap(err)).Should(BeAssignableToTypeOf(new(tinysl.ConstructorNotFoundError)))
+		})
+
+		It("should return error if used with Decorate or Replace", func() {
+			_, err := tinysl.
+				Add(tinysl.Singleton, nameServiceConstructor).
+				Decorate(tinysl.Singleton, nameServiceDecoratorConstructor("Mr."), tinysl.AsDefault).
+				ServiceLocator()
+
+			Expect(err).Should(HaveOccurred())
+			Expect(errors.Unwrap(err)).Should(MatchError(tinysl.ErrDefaultUnsupported))
+
+			_, err = tinysl.
+				Add(tinysl.Singleton, nameServiceConstructor).
+				Replace(nameServiceConstructor, tinysl.AsDefault).
+				ServiceLocator()
+
+			Expect(err).Should(HaveOccurred())
+			Expect(errors.Unwrap(err)).Should(MatchError(tinysl.ErrDefaultUnsupported))
+		})
+	})
+
+	Context("Modules", func() {
+		heroes := tinysl.NewModule("heroes", func(c tinysl.Container) {
+			c.Add(tinysl.Singleton, nameServiceConstructor).
+				Add(tinysl.Singleton, heroConstructor)
+		}, tinysl.Export[*Hero]())
+
+		It("should use services inside of module and exported services outside of it", func() {
+			_, err := tinysl.
+				AddModule(heroes).
+				Add(tinysl.Singleton, disguisedImpostorConstructor, tinysl.WithName("disguised")).
+				Add(tinysl.Singleton, func(hero *Hero) *Impostor { return &Impostor{hero: hero} }).
+				ServiceLocator()
+
+			Expect(err).ShouldNot(HaveOccurred())
+		})
+
+		It("should return error if service depends on service that module does not export", func() {
+			_, err := tinysl.
+				AddModule(heroes).
+				Add(tinysl.Singleton, impostorConstructor).
+				ServiceLocator()
+
+			Expect(err).Should(HaveOccurred())
+			Expect(errors.Unwrap(err)).Should(BeAssignableToTypeOf(new(tinysl.ModuleVisibilityError)))
+			Expect(err.Error()).To(ContainSubstring(`it is not exported by module "heroes"`))
+		})
+
+		It("should return error if module depends on module it does not import", func() {
+			impostors := func(opts ...tinysl.ModuleOption) tinysl.Module {
+				return tinysl.NewModule("impostors", func(c tinysl.Container) {
+					c.Add(tinysl.Singleton, func(hero *Hero) *Impostor { return &Impostor{hero: hero} })
+				}, opts...)
+			}
+
+			_, err := tinysl.
+				AddModule(heroes).
+				AddModule(impostors()).
+				ServiceLocator()
+
+			Expect(err).Should(HaveOccurred())
+			Expect(errors.Unwrap(err)).Should(BeAssignableToTypeOf(new(tinysl.ModuleVisibilityError)))
+			Expect(errors.Unwrap(err).(*tinysl.ModuleVisibilityError).Exported).To(BeTrue())
+
+			_, err = tinysl.
+				AddModule(heroes).
+				AddModule(impostors(tinysl.Import(heroes))).
+				ServiceLocator()
+
+			Expect(err).ShouldNot(HaveOccurred())
+		})
+
+		It("should return error if imported module is not added", func() {
+			_, err := tinysl.
+				AddModule(tinysl.NewModule("impostors", func(c tinysl.Container) {
+					c.Add(tinysl.Singleton, func(hero *Hero) *Impostor { return &Impostor{hero: hero} })
+				}, tinysl.Import(heroes))).
+				ServiceLocator()
+
+			Expect(err).Should(HaveOccurred())
+			Expect(err).Should(BeAssignableToTypeOf(new(tinysl.ModuleError)))
+			Expect(errors.Unwrap(err)).Should(BeAssignableToTypeOf(new(tinysl.ModuleNotAddedError)))
+		})
+
+		It("should return error if module exports service it does not register", func() {
+			_, err := tinysl.
+				AddModule(tinysl.NewModule("names", func(c tinysl.Container) {
+					c.Add(tinysl.Singleton, nameServiceConstructor)
+				}, tinysl.Export[*Hero]())).
+				ServiceLocator()
+
+			Expect(err).Should(HaveOccurred())
+			Expect(errors.Unwrap(err)).Should(BeAssignableToTypeOf(new(tinysl.ExportNotRegisteredError)))
+		})
+
+		It("should return error if module registration failed", func() {
+			_, err := tinysl.
+				AddModule(tinysl.NewModule("names", func(c tinysl.Container) {
+					c.Add(tinysl.Singleton, nameServiceConstructor).
+						Add(tinysl.Singleton, nameServiceConstructor)
+				})).
+				ServiceLocator()
+
+			Expect(err).Should(HaveOccurred())
+			Expect(err).Should(BeAssignableToTypeOf(new(tinysl.ModuleError)))
+			Expect(errors.Unwrap(errors.Unwrap(err))).Should(MatchError(tinysl.ErrDuplicateConstructor))
+		})
+
+		It("should return error if module or its service was already added", func() {
+			_, err := tinysl.
+				AddModule(heroes).
+				AddModule(heroes).
+				ServiceLocator()
+
+			Expect(err).Should(HaveOccurred())
+			Expect(errors.Unwrap(err)).Should(MatchError(tinysl.ErrDuplicateModule))
+
+			_, err = tinysl.
+				Add(tinysl.Singleton, heroConstructor).
+				AddModule(heroes).
+				ServiceLocator()
+
+			Expect(err).Should(HaveOccurred())
+			Expect(errors.Unwrap(errors.Unwrap(err))).Should(MatchError(tinysl.ErrDuplicateConstructor))
+		})
+
+		It("should keep services modules do not export apart", func() {
+			villains := tinysl.NewModule("villains", func(c tinysl.Container) {
+				c.Add(tinysl.Singleton, func() (NameService, error) { return NameProvider("Joker"), nil }).
+					Add(tinysl.Singleton, impostorConstructor)
+			}, tinysl.Export[*Impostor](), tinysl.Import(heroes))
+
+			sl, err := tinysl.
+				Add(tinysl.Singleton, func() (NameService, error) { return NameProvider("Alfred"), nil }).
+				AddModule(heroes).
+				AddModule(villains).
+				ServiceLocator()
+
+			Expect(err).ShouldNot(HaveOccurred())
+
+			ctx := context.Background()
+			Expect(tinysl.MustGet[*Hero](ctx, sl).Announce()).To(Equal("Bob is our hero!"))
+			Expect(tinysl.MustGet[*Impostor](ctx, sl).Name()).To(Equal("Joker"))
+			Expect(tinysl.MustGet[NameService](ctx, sl).Name()).To(Equal("Alfred"))
+		})
+
+		It("should return error if module decorates service it does not register", func() {
+			_, err := tinysl.
+				Add(tinysl.Singleton, heroConstructor).
+				Add(tinysl.Singleton, nameServiceConstructor).
+				AddModule(tinysl.NewModule("impostors", func(c tinysl.Container) {
+					c.Decorate(tinysl.Singleton, func(hero *Hero) *Hero { return &Hero{name: "impostor"} })
+				})).
+				ServiceLocator()
+
+			Expect(err).Should(HaveOccurred())
+			Expect(err).Should(BeAssignableToTypeOf(new(tinysl.ModuleError)))
+			Expect(err).Should(MatchError(tinysl.ErrDecoratorOutsideModule))
+		})
+
+		It("should return error if module has no name", func() {
+			_, err := tinysl.
+				AddModule(tinysl.NewModule("", nil)).
+				ServiceLocator()
+
+			Expect(err).Should(HaveOccurred())
+			Expect(errors.Unwrap(err)).Should(MatchError(tinysl.ErrModuleWithoutName))
+		})
+	})
+
+	Context("WhenBuilding", func() {
+		It("should return an error if dependency registered when building consumer has shorter lifetime", func() {
+			_, err := tinysl.
+				Add(tinysl.Singleton, nameServiceConstructor).
+				Add(tinysl.PerContext, nameServiceConstructor, tinysl.WhenBuilding[*Hero]()).
+				Add(tinysl.Singleton, heroConstructor).
+				ServiceLocator()
+
+			Expect(err).Should(HaveOccurred())
+			Expect(errors.Unwrap(err)).Should(BeAssignableToTypeOf(new(tinysl.ScopeHierarchyError)))
+		})
+		It("should return an error if other consumers depend on service that is not registered", func() {
+			_, err := tinysl.
+				Add(tinysl.Singleton, nameServiceConstructor, tinysl.WhenBuilding[*Hero]()).
+				Add(tinysl.Singleton, heroConstructor).
+				Add(tinysl.Singleton, impostorConstructor).
+				ServiceLocator()
+
+			Expect(err).Should(HaveOccurred())
+			Expect(errors.Unwrap(err)).Should(BeAssignableToTypeOf(new(tinysl.ConstructorNotFoundError)))
+		})
+		It("should return an error if consumer already has registration of the same type", func() {
+			_, err := tinysl.
+				Add(tinysl.Singleton, nameServiceConstructor, tinysl.WhenBuilding[*Hero](), tinysl.WhenBuilding[*Impostor]()).
+				Add(tinysl.Singleton, nameServiceConstructor, tinysl.WhenBuilding[*Hero]()).
+				ServiceLocator()
+
+			Expect(err).Should(HaveOccurred())
+			Expect(errors.Unwrap(err)).Should(MatchError(tinysl.ErrDuplicateConstructor))
+		})
+		It("should return an error if used with unsupported options", func() {
+			_, err := tinysl.
+				Add(tinysl.Singleton, nameServiceConstructor, tinysl.WhenBuilding[*Hero](), tinysl.AsGroupMember).
+				ServiceLocator()
+
+			Expect(err).Should(HaveOccurred())
+			Expect(errors.Unwrap(err)).Should(MatchError(tinysl.ErrWhenBuildingUnsupported))
+
+			_, err = tinysl.
+				Add(tinysl.Singleton, nameServiceConstructor).
+				Decorate(tinysl.Singleton, nameServiceDecoratorConstructor("decorated"), tinysl.WhenBuilding[*Hero]()).
+				ServiceLocator()
+
+			Expect(err).Should(HaveOccurred())
+			Expect(errors.Unwrap(err)).Should(MatchError(tinysl.ErrWhenBuildingUnsupported))
+
+			_, err = tinysl.
+				AddFactory(heroFactoryWithCleanup(func() {}), tinysl.WhenBuilding[*Impostor]()).
+				ServiceLocator()
+
+			Expect(err).Should(HaveOccurred())
+			Expect(errors.Unwrap(err)).Should(MatchError(tinysl.ErrFactoryUnsupportedOption))
+		})
+	})
+
+	Context("PerInnermostScope", func() {
+		It("should return an error if service resolved from the outermost scope depends on innermost one", func() {
+			_, err := tinysl.
+				Add(tinysl.PerContext, nameServiceConstructor, tinysl.PerInnermostScope).
+				Add(tinysl.PerContext, heroConstructor).
+				ServiceLocator()
+
+			Expect(err).Should(HaveOccurred())
+			Expect(errors.Unwrap(err)).Should(BeAssignableToTypeOf(new(tinysl.ScopeHierarchyError)))
+
+			_, err = tinysl.
+				Add(tinysl.PerContext, nameServiceConstructor).
+				Add(tinysl.PerContext, heroConstructor, tinysl.PerInnermostScope).
+				ServiceLocator()
+
+			Expect(err).ShouldNot(HaveOccurred())
+		})
+		It("should return an error if used not with PerContext constructor", func() {
+			_, err := tinysl.
+				Add(tinysl.Singleton, nameServiceConstructor, tinysl.PerInnermostScope).
+				ServiceLocator()
+
+			Expect(err).Should(HaveOccurred())
+			Expect(errors.Unwrap(err)).Should(MatchError(tinysl.ErrInnermostScopeNotPerContext))
+
+			_, err = tinysl.
+				AddValue(&Hero{"Bob"}, tinysl.PerInnermostScope).
+				ServiceLocator()
+
+			Expect(err).Should(HaveOccurred())
+			Expect(errors.Unwrap(err)).Should(MatchError(tinysl.ErrInnermostScopeNotPerContext))
 		})
+	})
 
-		It("should be tread-safe", func() {
-			sl := tinysl.New(tinysl.SilenceUseSingletonWarnings)
+	Context("NewChild", func() {
+		It("should use services of parent as dependencies", func() {
+			parent, err := tinysl.
+				Add(tinysl.PerContext, nameServiceConstructor).
+				ServiceLocator()
+			Expect(err).ShouldNot(HaveOccurred())
 
-			var wg sync.WaitGroup
+			_, err = parent.
+				NewChild().
+				Add(tinysl.PerContext, heroConstructor).
+				Decorate(tinysl.PerContext, nameServiceDecoratorConstructor("decorated")).
+				ServiceLocator()
+			Expect(err).ShouldNot(HaveOccurred())
+		})
+		It("should return an error if service of child depends on parent service with shorter lifetime", func() {
+			parent, err := tinysl.
+				Add(tinysl.PerContext, nameServiceConstructor).
+				ServiceLocator()
+			Expect(err).ShouldNot(HaveOccurred())
 
-			wg.Add(1)
-			go func() {
-				defer GinkgoRecover()
+			_, err = parent.
+				NewChild().
+				Add(tinysl.Singleton, heroConstructor).
+				ServiceLocator()
 
-				_ = sl.Add(tinysl.PerContext, nameServiceConstructor)
+			Expect(err).Should(HaveOccurred())
+			Expect(errors.Unwrap(err)).Should(BeAssignableToTypeOf(new(tinysl.ScopeHierarchyError)))
+		})
+		It("should return an error if dependency is not registered by child or parent", func() {
+			parent, err := tinysl.
+				Add(tinysl.Singleton, nameProviderConstructor).
+				ServiceLocator()
+			Expect(err).ShouldNot(HaveOccurred())
 
-				wg.Done()
-			}()
+			_, err = parent.
+				NewChild().
+				Add(tinysl.Singleton, heroConstructor).
+				ServiceLocator()
 
-			wg.Add(1)
-			go func() {
-				defer GinkgoRecover()
+			Expect(err).Should(HaveOccurred())
+		})
+	})
 
-				_ = sl.Add(tinysl.PerContext, nameServiceConstructor)
+	Context("AddFactory", func() {
+		It("should register factory", func() {
+			_, err := tinysl.
+				AddFactory(heroFactoryWithCleanup(func() {})).
+				Add(tinysl.Singleton, nameServiceConstructor).
+				Add(tinysl.Singleton, func(tinysl.Factory[HeroArgs, *Hero]) *Impostor { return &Impostor{} }).
+				ServiceLocator()
 
-				wg.Done()
-			}()
+			Expect(err).ShouldNot(HaveOccurred())
+		})
 
-			wg.Wait()
+		It("should return error if factory does not take arguments", func() {
+			_, err := tinysl.
+				AddFactory(func(context.Context) *Hero { return &Hero{} }).
+				ServiceLocator()
 
-			_, err := sl.ServiceLocator()
 			Expect(err).Should(HaveOccurred())
+			Expect(err).Should(BeAssignableToTypeOf(new(tinysl.BadConstructorError)))
+			Expect(errors.Unwrap(err)).Should(MatchError(tinysl.ErrFactoryWithoutArgs))
 		})
 
-		It("should return error for circular dependencies", func() {
+		It("should return error if factory is used with unsupported option", func() {
 			_, err := tinysl.
-				Add(tinysl.Transient, nameServiceConstructor).
-				Add(tinysl.Transient, impostorConstructor).
-				Add(tinysl.Transient, disguisedImpostorConstructor).
+				AddFactory(heroFactoryWithCleanup(func() {}), tinysl.AsGroupMember).
 				ServiceLocator()
 
 			Expect(err).Should(HaveOccurred())
-			Expect(err).Should(BeAssignableToTypeOf(new(tinysl.ServiceBuilderError)))
-			Expect(errors.Unwrap(err)).Should(BeAssignableToTypeOf(new(tinysl.CircularDependencyError)))
+			Expect(err).Should(BeAssignableToTypeOf(new(tinysl.BadConstructorError)))
+			Expect(errors.Unwrap(err)).Should(MatchError(tinysl.ErrFactoryUnsupportedOption))
 		})
 
-		It("should return error for missing dependency", func() {
+		It("should return error if factory with same arguments was already added", func() {
 			_, err := tinysl.
-				Add(tinysl.Transient, nameServiceConstructor).
-				Add(tinysl.Transient, tableTimerConstructor).
-				Add(tinysl.Transient, impostorConstructor).
+				AddFactory(heroFactoryWithCleanup(func() {})).
+				AddFactory(func(args HeroArgs) *Hero { return &Hero{args.Title} }).
 				ServiceLocator()
 
 			Expect(err).Should(HaveOccurred())
-			Expect(err).Should(BeAssignableToTypeOf(new(tinysl.ServiceBuilderError)))
-			Expect(errors.Unwrap(err)).Should(BeAssignableToTypeOf(new(tinysl.ConstructorNotFoundError)))
+			Expect(errors.Unwrap(err)).Should(MatchError(tinysl.ErrDuplicateConstructor))
 		})
 
-		It("should return error for unsupported lifetime", func() {
+		It("should return error if Factory dependency has no factory", func() {
 			_, err := tinysl.
-				Add(4, nameServiceConstructor).
+				AddFactory(heroFactoryWithCleanup(func() {})).
+				Add(tinysl.Singleton, nameServiceConstructor).
+				Add(tinysl.Singleton, func(tinysl.Factory[string, *Hero]) *Impostor { return &Impostor{} }).
 				ServiceLocator()
 
 			Expect(err).Should(HaveOccurred())
-			Expect(err).Should(BeAssignableToTypeOf(tinysl.LifetimeUnsupportedError("")))
+			Expect(errors.Unwrap(err)).Should(BeAssignableToTypeOf(new(tinysl.ConstructorNotFoundError)))
 		})
 
-		It("should return error for wrong constructor type", func() {
+		It("should return error if factory dependency is not registered", func() {
 			_, err := tinysl.
-				Add(tinysl.Transient, "just random human made mistake").
+				AddFactory(heroFactoryWithCleanup(func() {})).
 				ServiceLocator()
 
 			Expect(err).Should(HaveOccurred())
-			Expect(err).Should(BeAssignableToTypeOf(new(tinysl.BadConstructorError)))
-			Expect(errors.Unwrap(err)).Should(BeAssignableToTypeOf(new(tinysl.ConstructorTemplateError)))
+			Expect(errors.Unwrap(err)).Should(BeAssignableToTypeOf(new(tinysl.ConstructorNotFoundError)))
 		})
+	})
 
-		It("should return error for constructor returning wrong type", func() {
-			badConstructor1 := func() error {
-				return nil
-			}
+	Context("In", func() {
+		It("should resolve parameter object fields as dependencies", func() {
+			_, err := tinysl.
+				Add(tinysl.PerContext, heroWithParamsConstructor).
+				Add(tinysl.Singleton, nameServiceConstructor, tinysl.WithName("hero")).
+				Add(tinysl.Singleton, func() Announcer { return &Hero{"Sam"} }, tinysl.AsGroupMember).
+				ServiceLocator()
 
-			badConstructor2 := func() (int, bool) {
-				return 0, false
-			}
+			Expect(err).ShouldNot(HaveOccurred())
+		})
 
-			badConstructor3 := func() (int, bool, error) {
-				return 0, false, nil
-			}
+		It("should return error if parameter object field is not registered", func() {
+			_, err := tinysl.
+				Add(tinysl.PerContext, heroWithParamsConstructor).
+				Add(tinysl.Singleton, nameServiceConstructor).
+				ServiceLocator()
 
-			badConstructor4 := func() (int, tinysl.Cleanup, bool) {
-				return 0, func() {}, false
-			}
+			Expect(err).Should(HaveOccurred())
 
-			badConstructor5 := func() (int, error, tinysl.Cleanup) {
-				return 0, nil, func() {}
-			}
+			notFoundErr := new(tinysl.ConstructorNotFoundError)
+			Expect(errors.As(err, &notFoundErr)).To(BeTrue())
+			Expect(notFoundErr.Name).To(Equal("hero"))
+		})
 
-			badConstructor6 := func() (int, bool, tinysl.Cleanup, error) {
-				return 0, false, func() {}, nil
+		It("should return error on unknown tag option", func() {
+			type params struct {
+				tinysl.In
+
+				NameService NameService `tinysl:"named=hero"`
 			}
 
 			_, err := tinysl.
-				Add(tinysl.Transient, badConstructor1).
+				Add(tinysl.Singleton, func(params) *Hero { return &Hero{} }).
 				ServiceLocator()
 
 			Expect(err).Should(HaveOccurred())
 			Expect(err).Should(BeAssignableToTypeOf(new(tinysl.BadConstructorError)))
-			Expect(errors.Unwrap(err)).Should(BeAssignableToTypeOf(new(tinysl.ConstructorTemplateError)))
+			Expect(errors.Unwrap(err)).Should(BeAssignableToTypeOf(new(tinysl.TagError)))
+			Expect(errors.Is(err, tinysl.ErrUnknownTagOption)).To(BeTrue())
+		})
 
-			_, err = tinysl.
-				Add(tinysl.Transient, badConstructor2).
-				ServiceLocator()
+		It("should return error on group tag used with not a slice", func() {
+			type params struct {
+				tinysl.In
 
-			Expect(err).Should(HaveOccurred())
-			Expect(err).Should(BeAssignableToTypeOf(new(tinysl.BadConstructorError)))
-			Expect(errors.Unwrap(err)).Should(BeAssignableToTypeOf(new(tinysl.ConstructorTemplateError)))
+				NameService NameService `tinysl:"group"`
+			}
 
-			_, err = tinysl.
-				Add(tinysl.Transient, badConstructor3).
+			_, err := tinysl.
+				Add(tinysl.Singleton, func(params) *Hero { return &Hero{} }).
 				ServiceLocator()
 
 			Expect(err).Should(HaveOccurred())
-			Expect(err).Should(BeAssignableToTypeOf(new(tinysl.BadConstructorError)))
-			Expect(errors.Unwrap(err)).Should(BeAssignableToTypeOf(new(tinysl.ConstructorTemplateError)))
+			Expect(errors.Is(err, tinysl.ErrGroupTagNotSlice)).To(BeTrue())
+		})
+	})
 
-			_, err = tinysl.
-				Add(tinysl.Transient, badConstructor4).
+	Context("Out", func() {
+		It("should register result object fields as services", func() {
+			_, err := tinysl.
+				Add(tinysl.Singleton, heroesResultConstructor(func() {})).
+				Add(tinysl.PerContext, tableTimerConstructor).
 				ServiceLocator()
 
-			Expect(err).Should(HaveOccurred())
-			Expect(err).Should(BeAssignableToTypeOf(new(tinysl.BadConstructorError)))
-			Expect(errors.Unwrap(err)).Should(BeAssignableToTypeOf(new(tinysl.ConstructorTemplateError)))
+			Expect(err).ShouldNot(HaveOccurred())
+		})
 
-			_, err = tinysl.
-				Add(tinysl.Transient, badConstructor5).
+		It("should return error if result object field was already added", func() {
+			_, err := tinysl.
+				Add(tinysl.Singleton, nameServiceConstructor).
+				Add(tinysl.Singleton, heroesResultConstructor(func() {})).
 				ServiceLocator()
 
 			Expect(err).Should(HaveOccurred())
 			Expect(err).Should(BeAssignableToTypeOf(new(tinysl.BadConstructorError)))
-			Expect(errors.Unwrap(err)).Should(BeAssignableToTypeOf(new(tinysl.ConstructorTemplateError)))
+			Expect(errors.Unwrap(err)).Should(MatchError(tinysl.ErrDuplicateConstructor))
+		})
 
-			_, err = tinysl.
-				Add(tinysl.Transient, badConstructor6).
+		It("should return error if result object is used with unsupported option", func() {
+			_, err := tinysl.
+				Add(tinysl.Singleton, heroesResultConstructor(func() {}), tinysl.AsGroupMember).
 				ServiceLocator()
 
 			Expect(err).Should(HaveOccurred())
-			Expect(err).Should(BeAssignableToTypeOf(new(tinysl.BadConstructorError)))
-			Expect(errors.Unwrap(err)).Should(BeAssignableToTypeOf(new(tinysl.ConstructorTemplateError)))
+			Expect(errors.Unwrap(err)).Should(MatchError(tinysl.ErrResultObjectOption))
 		})
 
-		It("should return error for constructor with context.Context not as a first argument", func() {
-			badConstructor := func(nameService NameService, ctx context.Context) (*Hero, error) {
-				return &Hero{name: nameService.Name()}, nil
+		It("should return error if result object field is optional", func() {
+			type result struct {
+				tinysl.Out
+
+				NameService NameService `tinysl:"optional"`
 			}
 
 			_, err := tinysl.
-				Add(tinysl.Transient, nameServiceConstructor).
-				Add(tinysl.Transient, badConstructor).
+				Add(tinysl.Singleton, func() result { return result{} }).
 				ServiceLocator()
 
 			Expect(err).Should(HaveOccurred())
-			Expect(err).Should(BeAssignableToTypeOf(new(tinysl.BadConstructorError)))
-			Expect(errors.Unwrap(err)).Should(BeAssignableToTypeOf(new(tinysl.ConstructorTemplateError)))
+			Expect(errors.Is(err, tinysl.ErrOptionalResultField)).To(BeTrue())
 		})
 
-		It("should return error for constructor if dependency tree does not respect lifetime hierarchy", func() {
+		It("should return error if constructor depends on its own result field", func() {
+			type result struct {
+				tinysl.Out
+
+				Hero *Hero
+			}
+
 			_, err := tinysl.
-				Add(tinysl.PerContext, tableTimerConstructor).
-				Add(tinysl.Transient, nameServiceConstructor).
+				Add(tinysl.Singleton, func(*Hero) result { return result{} }).
 				ServiceLocator()
 
 			Expect(err).Should(HaveOccurred())
-			Expect(err).Should(BeAssignableToTypeOf(new(tinysl.ServiceBuilderError)))
-			Expect(errors.Unwrap(err)).Should(BeAssignableToTypeOf(new(tinysl.ScopeHierarchyError)))
+			Expect(errors.Unwrap(err)).Should(BeAssignableToTypeOf(new(tinysl.CircularDependencyError)))
 		})
 
-		It("should return error for constructor if service can be made Singleton", func() {
-			var buf bytes.Buffer
-
-			slog.SetDefault(
-				slog.New(
-					slog.NewTextHandler(
-						&buf,
-						&slog.HandlerOptions{
-							ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
-								if a.Key == slog.TimeKey && len(groups) == 0 {
-									return slog.Attr{}
-								}
-								return a
-							},
-						},
-					),
-				),
-			)
+		It("should replace result object fields together with result object", func() {
 			_, err := tinysl.
-				Add(tinysl.PerContext, heroConstructor).
-				Add(tinysl.Singleton, nameServiceConstructor).
+				Add(tinysl.Singleton, heroesResultConstructor(func() {})).
+				Replace(heroesResultConstructor(func() {})).
 				ServiceLocator()
 
 			Expect(err).ShouldNot(HaveOccurred())
-			Expect(buf.String()).To(Equal("level=INFO msg=\"ERROR your dependency hierarchy can be optimised error=\\\"PerContext *tinysl_test.Hero should be a Singleton\\\"\"\n"))
-			slog.SetDefault(slog.Default())
 		})
+	})
 
-		It("should ignore scope analyzer errors if said so", func() {
-			var buf bytes.Buffer
+	Context("ResolveByAssignability", func() {
+		It("should not resolve interface by implementation by default", func() {
+			_, err := tinysl.
+				Add(tinysl.Singleton, nameProviderConstructor).
+				Add(tinysl.Singleton, heroConstructor).
+				ServiceLocator()
 
-			slog.SetDefault(
-				slog.New(
-					slog.NewTextHandler(
-						&buf,
-						&slog.HandlerOptions{
-							ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
-								if a.Key == slog.TimeKey && len(groups) == 0 {
-									return slog.Attr{}
-								}
-								return a
-							},
-						},
-					),
-				),
-			)
+			Expect(err).Should(HaveOccurred())
+			Expect(errors.Unwrap(err)).Should(BeAssignableToTypeOf(new(tinysl.ConstructorNotFoundError)))
+		})
 
+		It("should resolve interface dependency by its only implementation", func() {
 			_, err := tinysl.
-				New(tinysl.SilenceUseSingletonWarnings).
-				Add(tinysl.PerContext, tableTimerConstructor).
-				Add(tinysl.Transient, nameServiceConstructor).
+				New(tinysl.ResolveByAssignability).
+				Add(tinysl.Singleton, nameProviderConstructor).
+				Add(tinysl.Singleton, heroConstructor).
 				ServiceLocator()
 
 			Expect(err).ShouldNot(HaveOccurred())
-			Expect(buf.String()).To(BeEmpty())
-			slog.SetDefault(slog.Default())
 		})
 
-		It("should return first encountered error", func() {
+		It("should return error listing candidates if interface has several implementations", func() {
 			_, err := tinysl.
-				Add(tinysl.Transient, "just random human made mistake").
-				Add(4, nameServiceConstructor).
+				New(tinysl.ResolveByAssignability).
+				Add(tinysl.Singleton, nameProviderConstructor).
+				Add(tinysl.Singleton, func() *Impostor { return &Impostor{name: "Bob"} }).
+				Add(tinysl.Singleton, heroConstructor).
 				ServiceLocator()
 
 			Expect(err).Should(HaveOccurred())
-			Expect(err).Should(BeAssignableToTypeOf(new(tinysl.BadConstructorError)))
-			Expect(errors.Unwrap(err)).Should(BeAssignableToTypeOf(new(tinysl.ConstructorTemplateError)))
+			Expect(err).Should(BeAssignableToTypeOf(new(tinysl.ServiceBuilderError)))
+
+			ambiguousErr := new(tinysl.AmbiguousDependencyError)
+			Expect(errors.As(err, &ambiguousErr)).To(BeTrue())
+			Expect(ambiguousErr.Candidates).To(Equal([]reflect.Type{
+				reflect.TypeOf(new(Impostor)),
+				reflect.TypeOf(NameProvider("")),
+			}))
+		})
+
+		It("should report inferred binding in scope hierarchy error", func() {
+			_, err := tinysl.
+				New(tinysl.ResolveByAssignability).
+				Add(tinysl.PerContext, func(context.Context) NameProvider { return "Bob" }).
+				Add(tinysl.Singleton, heroConstructor).
+				ServiceLocator()
+
+			Expect(err).Should(HaveOccurred())
+			Expect(errors.Unwrap(err)).Should(BeAssignableToTypeOf(new(tinysl.ScopeHierarchyError)))
+			Expect(err.Error()).To(ContainSubstring("tinysl_test.NameService inferred as tinysl_test.NameProvider"))
 		})
 	})
+
 	Context("T, P and I constructors", func() {
 		It("should return error if T is not called with a struct type argument", func() {
 			_, err := tinysl.
@@ -561,6 +1584,52 @@ var _ = Describe("Container", func() {
 			Expect(errors.Unwrap(err)).Should(BeAssignableToTypeOf(new(tinysl.ConstructorNotFoundError)))
 		})
 
+		It("should replace named constructor", func() {
+			sl, err := tinysl.
+				Add(tinysl.Singleton, nameServiceConstructor).
+				Add(tinysl.Singleton, nameServiceConstructor, tinysl.WithName("replica")).
+				Replace(func() NameService {
+					return NameProvider("Sam")
+				}, tinysl.WithName("replica")).
+				ServiceLocator()
+			Expect(err).ShouldNot(HaveOccurred())
+
+			s, err := tinysl.GetNamed[NameService](context.TODO(), sl, "replica")
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(s.Name()).To(Equal("Sam"))
+		})
+
+		It("should keep interfaces of replaced constructor", func() {
+			sl, err := tinysl.
+				Add(tinysl.Singleton, func() *Impostor { return &Impostor{name: "Bob"} }, tinysl.As[NameService]()).
+				Replace(func() *Impostor { return &Impostor{name: "Sam"} }).
+				ServiceLocator()
+			Expect(err).ShouldNot(HaveOccurred())
+
+			impostor, err := tinysl.Get[*Impostor](context.TODO(), sl)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			s, err := tinysl.Get[NameService](context.TODO(), sl)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(s).To(BeIdenticalTo(impostor))
+			Expect(s.Name()).To(Equal("Sam"))
+		})
+
+		It("should bind replacement only to its own interfaces if it has them", func() {
+			sl, err := tinysl.
+				Add(tinysl.Singleton, func() *Impostor { return &Impostor{name: "Bob"} }, tinysl.As[NameService]()).
+				Replace(func() *Impostor { return &Impostor{name: "Sam"} }, tinysl.As[Announcer]()).
+				ServiceLocator()
+			Expect(err).ShouldNot(HaveOccurred())
+
+			_, err = tinysl.Get[Announcer](context.TODO(), sl)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			_, err = tinysl.Get[NameService](context.TODO(), sl)
+			Expect(err).Should(HaveOccurred())
+			Expect(err).Should(BeAssignableToTypeOf(new(tinysl.ConstructorNotFoundError)))
+		})
+
 		It("should report an error if replacement is not a function", func() {
 			_, err := tinysl.
 				Add(tinysl.PerContext, heroConstructor).
@@ -599,6 +1668,22 @@ var _ = Describe("Container", func() {
 			Expect(err).Should(BeAssignableToTypeOf(new(tinysl.ServiceBuilderError)))
 			Expect(errors.Unwrap(err)).Should(MatchError(tinysl.ErrDecoratorHasNothingToDecorate))
 		})
+		It("should decorate named constructor", func() {
+			sl, err := tinysl.
+				Add(tinysl.Singleton, nameServiceConstructor).
+				Add(tinysl.Singleton, nameServiceConstructor, tinysl.WithName("replica")).
+				Decorate(tinysl.Singleton, nameServiceDecoratorConstructor("decorated"), tinysl.WithName("replica")).
+				ServiceLocator()
+			Expect(err).ShouldNot(HaveOccurred())
+
+			s, err := tinysl.Get[NameService](context.TODO(), sl)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(s.Name()).To(Equal("Bob"))
+
+			s, err = tinysl.GetNamed[NameService](context.TODO(), sl, "replica")
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(s.Name()).To(Equal("decorated Bob"))
+		})
 		It("should return an error if decorator does not depend on decorated type", func() {
 			_, err := tinysl.
 				Add(tinysl.PerContext, nameProviderConstructor).
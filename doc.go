@@ -71,9 +71,29 @@ How to use:
 Functions:
   - tinysl.New
   - tinysl.Add
+  - tinysl.AddValue
+  - tinysl.AddFactory
+  - tinysl.AddProvider
+  - tinysl.AddModule
+  - tinysl.NewModule
   - tinysl.Get
   - tinysl.MustGet
   - tinysl.Prepare
+  - tinysl.GetAll
+  - tinysl.GetNamed
+  - tinysl.MustGetNamed
+  - tinysl.PrepareNamed
+  - tinysl.Inject
+  - tinysl.Invoke
+  - tinysl.NewScope
+  - tinysl.AwaitCleanup
+  - tinysl.Detach
+  - tinysl.Go
+  - tinysl.WithScopedValue
+  - tinysl.Override
+  - tinysl.OverrideNamed
+  - tinysl.OverrideFunc
+  - tinysl.OverrideNamedFunc
   - tinysl.DecorateHandler
   - tinysl.DecorateMiddleware
   - tinysl.SetLogger
@@ -94,5 +114,101 @@ Public fields constructor
   - tinysl.T[Type] - would return Type instance with filled public fields using registered constructors.
   - tinysl.P[Type] - would return *Type instance with filled public fields using registered constructors.
   - tinysl.I[Interface, Type] - would return Interface implemented by *Type instance with filled public fields using registered constructors.
+  - Fields of T, P and I can be tagged with the same `tinysl` tag options as fields of parameter objects.
+
+Values:
+  - Container.AddValue(value) - registers already constructed value as a Singleton of its type.
+  - tinysl.WithCleanup(fn) - cleanup function of registered value.
+
+Providers:
+  - Container.AddProvider(provider) - registers each exported method of provider as a constructor.
+    Methods prefixed with Transient or PerContext get that lifetime, all other methods are Singletons.
+  - tinysl.ProviderLifetimes - implemented by provider to choose lifetimes of its methods by their names.
+
+Modules:
+  - tinysl.NewModule(name, register, opts...) - groups registrations of a sub-system, used with Container.AddModule(module).
+  - tinysl.Export[T]() - module option allowing services of type T to be used outside of module.
+  - tinysl.Import(modules...) - module option allowing services exported by modules to be used inside of module.
+
+Default registrations:
+  - tinysl.AsDefault - registers constructor, value or factory used only if there is no regular registration of the same type and name.
+
+Contextual bindings:
+  - tinysl.WhenBuilding[T]() - registers constructor or value used instead of registration of the same type and name only to build services of type T.
+
+Explicit scopes:
+  - tinysl.NewScope(ctx) - returns context with PerContext scope shared by all contexts derived from it and function ending it.
+  - tinysl.PerInnermostScope - resolves PerContext service from the innermost of nested scopes instead of the outermost one.
+
+Awaiting cleanup:
+  - tinysl.AwaitCleanup(ctx) - waits until scope of ctx is cleaned up and returns panics recovered from its cleanups.
+  - tinysl.SyncCleanup - NewScope option making function ending the scope wait until it is cleaned up.
+
+Background goroutines:
+  - tinysl.Go(ctx, fn) - runs fn in new goroutine with context carrying PerContext scope of ctx, cleaned up after fn returns.
+  - tinysl.Detach(ctx) - returns context carrying PerContext scope of ctx that is not cancelled and function returning its lease.
+
+Scoped values:
+  - tinysl.Scoped[T] - PerContext constructor declaring value of type T supplied at runtime.
+  - tinysl.WithScopedValue[T](ctx, value) - returns context with value of type T seeded into its PerContext scope.
+
+Per-context overrides:
+  - tinysl.Override[T](ctx, value) - returns context in which PerContext or Transient service of type T is resolved as value.
+  - tinysl.OverrideFunc[T](ctx, constructor) - returns context in which PerContext or Transient service of type T is resolved with constructor.
+  - T can be interface service is bound to with As or by ResolveByAssignability, overrides of types that are not registered are ignored.
+
+Child containers:
+  - ServiceLocator.NewChild(opts...) - returns Container which ServiceLocator falls back to parent for services it does not register.
+    Singletons of parent are shared, Singletons of child are cleaned up with its WithSingletonCleanupContext context,
+    which defaults to context Singletons of parent are cleaned up with.
+  - Container.Child(opts...) - returns Container which falls back to registrations of parent for services it does not register.
+
+Named registrations:
+  - tinysl.WithName(name) - registers, decorates or replaces service with name.
+  - tinysl.WithNamedDependency[T](name) - resolves constructor dependency of type T using service registered with name,
+    used several times for the same T names dependencies of type T in order of parameters.
+
+Groups:
+  - tinysl.AsGroupMember - registers constructor as a member of group of its service type T.
+    All members are injected into constructors taking []T or ...T and returned by tinysl.GetAll[T].
+
+Maps:
+  - tinysl.WithMapKey(key) - registers constructor as an entry of map[string]T with key.
+    Map is injected into constructors taking map[string]T and returned by tinysl.Get[map[string]T].
+
+Interfaces:
+  - tinysl.As[I]() - additionally binds registration to interface I, sharing the same instance and lifetime.
+
+Resolving interfaces by implementation:
+  - tinysl.ResolveByAssignability - container option resolving interface that is not registered by the only service implementing it.
+  - ServiceLocator.Bindings() - lists available services including inferred bindings.
+
+Optional dependencies:
+  - tinysl.Optional[T] - constructor dependency that is not required to be registered.
+  - `tinysl:"optional"` - tag of public field of T, P or I that is left with zero value if its type is not registered.
+  - `tinysl:"-"` - tag of public field of T, P or I that is not filled.
+
+Lazy dependencies:
+  - tinysl.Lazy[T] - constructor dependency resolved when it is called, panics on error.
+  - tinysl.Provider[T] - constructor dependency resolved when it is called, returns error.
+
+Factories:
+  - Container.AddFactory(constructor) - registers constructor taking runtime arguments as its last parameter.
+  - tinysl.Factory[Args, T] - constructor dependency building new instance of T with arguments on each call.
+
+Parameter objects:
+  - tinysl.In - embedded in struct makes constructor parameter of that struct type resolve each of its public fields.
+  - `tinysl:"optional"` - field is left with zero value if its type is not registered.
+  - `tinysl:"name=<name>"` - field is resolved with service registered with name.
+  - `tinysl:"group"` or `tinysl:"group=<name>"` - slice field is resolved with (named) group.
+
+Result objects:
+  - tinysl.Out - embedded in struct returned by constructor registers each of its public fields as a separate service.
+  - `tinysl:"name=<name>"` - field is registered with name.
+  - `tinysl:"group"` or `tinysl:"group=<name>"` - field is registered as a member of (named) group.
+
+Inject and Invoke:
+  - tinysl.Inject(ctx, sl, &target) - fills public fields of existing struct following the same rules and tags as P.
+  - tinysl.Invoke(ctx, sl, fn) - calls fn with parameters resolved same as constructor dependencies and returns its results.
 */
 package tinysl
@@ -4,11 +4,11 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"slices"
+	"strings"
 )
 
 const (
-	contextDepName = "context.Context"
-
 	constructorTypeStr            string = "func(T1, ...) [T|(T, error)|(T, func(), error)]"
 	constructorWithContextTypeStr string = "func(context.Context, T1, ...) [T|(T, error)|(T, func(), error)]"
 
@@ -18,19 +18,50 @@ const (
 )
 
 var (
-	errorInterface   = reflect.TypeOf((*error)(nil)).Elem()
-	cleanUpType      = reflect.TypeOf((*func())(nil)).Elem()
-	contextInterface = reflect.TypeOf((*context.Context)(nil)).Elem()
+	errorInterface    = reflect.TypeOf((*error)(nil)).Elem()
+	stringType        = reflect.TypeOf("")
+	optionalInterface = reflect.TypeOf((*optionalDependency)(nil)).Elem()
+	lazyInterface     = reflect.TypeOf((*lazyDependency)(nil)).Elem()
+	factoryInterface  = reflect.TypeOf((*factoryDependency)(nil)).Elem()
+	inType            = reflect.TypeOf(In{})
+	outType           = reflect.TypeOf(Out{})
+	cleanUpType       = reflect.TypeOf((*func())(nil)).Elem()
+	contextInterface  = reflect.TypeOf((*context.Context)(nil)).Elem()
 
 	ErrDecoratorHasNothingToDecorate = fmt.Errorf("decorator has nothing to decorate")
 	ErrDecoratorBadDependency        = fmt.Errorf("decorator must depend on the same type it implements")
 	ErrConstructorNotAFunction       = fmt.Errorf("constructor must be a function")
-	ErrVariadicConstructor           = fmt.Errorf("variadic constructor is not supported")
+	ErrVariadicConstructor           = fmt.Errorf("variadic constructor is not supported without group to inject")
 	ErrDuplicateConstructor          = fmt.Errorf("ServiceLocator has already registered constructor for this type")
+	ErrNamedDependencyNotUsed        = fmt.Errorf("named dependency is not used by constructor")
+	ErrGroupMemberAndMapEntry        = fmt.Errorf("constructor cannot be both group member and map entry")
+	ErrNilValue                      = fmt.Errorf("value cannot be nil")
+	ErrCleanupWithoutValue           = fmt.Errorf("cleanup option can only be used with value")
+	ErrFactoryWithoutArgs            = fmt.Errorf("factory constructor must take arguments as its last parameter")
+	ErrFactoryUnsupportedOption      = fmt.Errorf("factory can only be used with WithName and WithNamedDependency options")
+	ErrUnknownTagOption              = fmt.Errorf("unknown tag option")
+	ErrGroupTagNotSlice              = fmt.Errorf("group tag can only be used with slice field")
+	ErrOptionalResultField           = fmt.Errorf("optional tag can not be used with result object field")
+	ErrResultObjectOption            = fmt.Errorf("result object can only be used with WithName and WithNamedDependency options")
 	ErrNilContext                    = fmt.Errorf("got nil context")
 	ErrIWrongTType                   = fmt.Errorf("I can be used only with T as a struct")
 	ErrIWrongIType                   = fmt.Errorf("I can be used only with I as an interface")
 	ErrITDoesNotImplementI           = fmt.Errorf("I can only be used with T if T or *T implements I")
+	ErrAsWrongType                   = fmt.Errorf("As can be used only with an interface")
+	ErrAsNotImplemented              = fmt.Errorf("As can only be used with interface implemented by service")
+	ErrAsWithGroupOrMap              = fmt.Errorf("As cannot be used with group member or map entry")
+	ErrProviderWithoutMethods        = fmt.Errorf("provider must have exported methods")
+	ErrProviderUnknownMethod         = fmt.Errorf("lifetime is set for method provider does not have")
+	ErrDefaultUnsupported            = fmt.Errorf("AsDefault can only be used with Add, AddValue and AddFactory")
+	ErrModuleWithoutName             = fmt.Errorf("module must have a name")
+	ErrDuplicateModule               = fmt.Errorf("module with this name is already added")
+	ErrDecoratorOutsideModule        = fmt.Errorf("module can decorate only services it registers")
+	ErrInjectWrongTarget             = fmt.Errorf("Inject can be used only with a non-nil pointer to a struct")
+	ErrInvokeNotAFunction            = fmt.Errorf("Invoke can be used only with a function")
+	ErrInnermostScopeNotPerContext   = fmt.Errorf("PerInnermostScope can only be used with PerContext constructor")
+	ErrAwaitWithoutScope             = fmt.Errorf("AwaitCleanup can not be used with context that is never done")
+	ErrOverrideSingleton             = fmt.Errorf("Singleton is shared between contexts and can not be overridden")
+	ErrWhenBuildingUnsupported       = fmt.Errorf("WhenBuilding can only be used with Add and AddValue without AsGroupMember, WithMapKey and As options")
 )
 
 func newConstructorUnsupportedError(constructorType reflect.Type, lifetime Lifetime) error {
@@ -90,22 +121,61 @@ func (err *BadConstructorError) Unwrap() error {
 	return err.cause
 }
 
+func newTagError(cause error, t reflect.Type, field string) error {
+	return &TagError{cause: cause, Struct: t, Field: field}
+}
+
+type TagError struct {
+	cause error
+
+	Struct reflect.Type
+	Field  string
+}
+
+func (err *TagError) Error() string {
+	return fmt.Sprintf("tinysl tag of %s.%s returned an error: %s", err.Struct, err.Field, err.cause)
+}
+
+func (err *TagError) Unwrap() error {
+	return err.cause
+}
+
 type TError struct {
+	cause error
+
 	T reflect.Type
 }
 
 func (err *TError) Error() string {
+	if err.cause != nil {
+		return fmt.Sprintf("tinysl.T[%s] returned an error: %s", err.T, err.cause)
+	}
+
 	return fmt.Sprintf("tinysl.T can only be used with a struct, got %s", err.T)
 }
 
+func (err *TError) Unwrap() error {
+	return err.cause
+}
+
 type PError struct {
+	cause error
+
 	T reflect.Type
 }
 
 func (err *PError) Error() string {
+	if err.cause != nil {
+		return fmt.Sprintf("tinysl.P[%s] returned an error: %s", err.T, err.cause)
+	}
+
 	return fmt.Sprintf("tinysl.P can only be used with a struct, got %s", err.T)
 }
 
+func (err *PError) Unwrap() error {
+	return err.cause
+}
+
 func newIError(cause error, i, t reflect.Type) error {
 	return &IError{T: t, I: i, cause: cause}
 }
@@ -124,6 +194,144 @@ func (err *IError) Unwrap() error {
 	return err.cause
 }
 
+func newAsError(cause error, as, t reflect.Type) error {
+	return &AsError{As: as, T: t, cause: cause}
+}
+
+type AsError struct {
+	cause error
+
+	As, T reflect.Type
+}
+
+func (err *AsError) Error() string {
+	return fmt.Sprintf("tinysl.As[%s] for %s returned an error: %s", err.As, err.T, err.cause)
+}
+
+func (err *AsError) Unwrap() error {
+	return err.cause
+}
+
+func newProviderError(cause error, provider reflect.Type, method string) error {
+	return &ProviderError{Provider: provider, Method: method, cause: cause}
+}
+
+type ProviderError struct {
+	cause error
+
+	Provider reflect.Type
+	Method   string
+}
+
+func (err *ProviderError) Error() string {
+	if err.Method != "" {
+		return fmt.Sprintf("provider %s method %s returned an error: %s", err.Provider, err.Method, err.cause)
+	}
+
+	return fmt.Sprintf("provider %s returned an error: %s", err.Provider, err.cause)
+}
+
+func (err *ProviderError) Unwrap() error {
+	return err.cause
+}
+
+func newModuleError(cause error, module string) error {
+	return &ModuleError{Module: module, cause: cause}
+}
+
+type ModuleError struct {
+	cause error
+
+	Module string
+}
+
+func (err *ModuleError) Error() string {
+	return fmt.Sprintf("module %q returned an error: %s", err.Module, err.cause)
+}
+
+func (err *ModuleError) Unwrap() error {
+	return err.cause
+}
+
+func newExportNotRegisteredError(t reflect.Type) error {
+	return &ExportNotRegisteredError{T: t}
+}
+
+type ExportNotRegisteredError struct {
+	T reflect.Type
+}
+
+func (err *ExportNotRegisteredError) Error() string {
+	return fmt.Sprintf("exported %s is not registered by module", err.T)
+}
+
+func newModuleNotAddedError(module string) error {
+	return &ModuleNotAddedError{Module: module}
+}
+
+type ModuleNotAddedError struct {
+	Module string
+}
+
+func (err *ModuleNotAddedError) Error() string {
+	return fmt.Sprintf("imported module %q is not added to container", err.Module)
+}
+
+func newModuleVisibilityError(depServiceName, module string, exported bool) error {
+	return &ModuleVisibilityError{DepServiceName: depServiceName, Module: module, Exported: exported}
+}
+
+// Reports dependency on service of module that is not exported by it or not imported by dependant module.
+type ModuleVisibilityError struct {
+	DepServiceName string
+	Module         string
+	Exported       bool
+}
+
+func (err *ModuleVisibilityError) Error() string {
+	if err.Exported {
+		return fmt.Sprintf("dependency on %s exported by module %q violates module visibility, module is not imported", err.DepServiceName, err.Module)
+	}
+
+	return fmt.Sprintf("dependency on %s violates module visibility, it is not exported by module %q", err.DepServiceName, err.Module)
+}
+
+func newInjectError(cause error, t reflect.Type) error {
+	return &InjectError{T: t, cause: cause}
+}
+
+type InjectError struct {
+	cause error
+
+	T reflect.Type
+}
+
+func (err *InjectError) Error() string {
+	return fmt.Sprintf("tinysl.Inject into %s returned an error: %s", err.T, err.cause)
+}
+
+func (err *InjectError) Unwrap() error {
+	return err.cause
+}
+
+func newInvokeError(cause error, fn reflect.Type) error {
+	return &InvokeError{Fn: fn, cause: cause}
+}
+
+type InvokeError struct {
+	cause error
+
+	Fn reflect.Type
+}
+
+func (err *InvokeError) Error() string {
+	return fmt.Sprintf("tinysl.Invoke of %s returned an error: %s", err.Fn, err.cause)
+}
+
+func (err *InvokeError) Unwrap() error {
+	return err.cause
+}
+
 type ConstructorTemplateError struct {
 	SupportedConstructorTemplates string
 	Lifetime                      Lifetime
@@ -137,33 +345,92 @@ func (err *ConstructorTemplateError) Error() string {
 	)
 }
 
-func newConstructorNotFoundError(typeName string) error {
+func newConstructorNotFoundError(serviceType reflect.Type, name string) error {
 	return &ConstructorNotFoundError{
-		TypeName: typeName,
+		ServiceType: serviceType,
+		Name:        name,
 	}
 }
 
 type ConstructorNotFoundError struct {
-	TypeName string
+	ServiceType reflect.Type
+	Name        string
 }
 
 func (err *ConstructorNotFoundError) Error() string {
-	return fmt.Sprintf("%s constructor not found", err.TypeName)
+	if err.Name != "" {
+		return fmt.Sprintf("%s named %q constructor not found", err.ServiceType, err.Name)
+	}
+
+	return fmt.Sprintf("%s constructor not found", err.ServiceType)
+}
+
+func newScopedValueNotSeededError(serviceType reflect.Type) error {
+	return &ScopedValueNotSeededError{ServiceType: serviceType}
+}
+
+type ScopedValueNotSeededError struct {
+	ServiceType reflect.Type
+}
+
+func (err *ScopedValueNotSeededError) Error() string {
+	return fmt.Sprintf("%s was not seeded into context with WithScopedValue", err.ServiceType)
+}
+
+func newAmbiguousDependencyError(serviceType reflect.Type, name string, candidates []reflect.Type) error {
+	slices.SortFunc(candidates, func(a, b reflect.Type) int { return strings.Compare(a.String(), b.String()) })
+
+	return &AmbiguousDependencyError{
+		ServiceType: serviceType,
+		Name:        name,
+		Candidates:  candidates,
+	}
+}
+
+type AmbiguousDependencyError struct {
+	ServiceType reflect.Type
+	Name        string
+	Candidates  []reflect.Type
+}
+
+func (err *AmbiguousDependencyError) Error() string {
+	candidates := make([]string, len(err.Candidates))
+	for i, candidate := range err.Candidates {
+		candidates[i] = candidate.String()
+	}
+
+	if err.Name != "" {
+		return fmt.Sprintf(
+			"%s named %q is implemented by several services: %s",
+			err.ServiceType, err.Name, strings.Join(candidates, ", "),
+		)
+	}
+
+	return fmt.Sprintf("%s is implemented by several services: %s", err.ServiceType, strings.Join(candidates, ", "))
 }
 
-func newCircularDependencyError(constructor any, dependency string) error {
+func newCircularDependencyError(constructor any, dependency reflect.Type, name string) error {
 	return &CircularDependencyError{
-		Dependency:  dependency,
-		Constructor: constructor,
+		Dependency:     dependency,
+		DependencyName: name,
+		Constructor:    constructor,
 	}
 }
 
 type CircularDependencyError struct {
-	Constructor any
-	Dependency  string
+	Constructor    any
+	Dependency     reflect.Type
+	DependencyName string
 }
 
 func (err *CircularDependencyError) Error() string {
+	if err.DependencyName != "" {
+		return fmt.Sprintf(
+			"%s named %q in %T is dependant on returned type",
+			err.Dependency, err.DependencyName, err.Constructor,
+		)
+	}
+
 	return fmt.Sprintf("%s in %T is dependant on returned type", err.Dependency, err.Constructor)
 }
 
@@ -184,6 +451,18 @@ func (err *ScopeHierarchyError) Error() string {
 	)
 }
 
+func newDuplicateMapKeyError(key string) error {
+	return &DuplicateMapKeyError{Key: key}
+}
+
+type DuplicateMapKeyError struct {
+	Key string
+}
+
+func (err *DuplicateMapKeyError) Error() string {
+	return fmt.Sprintf("map key %q is registered more than once", err.Key)
+}
+
 func newServiceBuilderError(cause error, lifetime Lifetime, typeName string) error {
 	return &ServiceBuilderError{
 		cause:    cause,
@@ -192,13 +471,27 @@ func newServiceBuilderError(cause error, lifetime Lifetime, typeName string) err
 	}
 }
 
+func newRecordBuilderError(cause error, r record) error {
+	return &ServiceBuilderError{
+		cause:    cause,
+		Lifetime: r.lifetime,
+		TypeName: r.key().String(),
+		Value:    r.value,
+	}
+}
+
 type ServiceBuilderError struct {
 	cause    error
 	TypeName string
 	Lifetime Lifetime
+	Value    bool
 }
 
 func (err *ServiceBuilderError) Error() string {
+	if err.Value {
+		return fmt.Sprintf("cannot build value %s: %s", err.TypeName, err.cause)
+	}
+
 	return fmt.Sprintf("cannot build %s %s: %s", err.Lifetime, err.TypeName, err.cause)
 }
 
@@ -0,0 +1,143 @@
+package tinysl
+
+import (
+	"context"
+	"sync"
+)
+
+type detachedKey struct{}
+
+// Leases of PerContext scopes by scope identity: *explicitScope or context.Context the scope is keyed by.
+var leases sync.Map
+
+// Defers cleanup of scope until all its leases are returned.
+type scopeLease struct {
+	deferred []func()
+	count    int
+	removed  bool
+	mu       sync.Mutex
+}
+
+// Returns context carrying PerContext scope of ctx, that is not cancelled when ctx is,
+// and function returning lease of the scope. Scope and its cleanups are deferred until all leases are returned,
+// so services resolved with detached context can still be used after ctx is done.
+// Leasing scope nested with NewScope leases its parents as well.
+func Detach(ctx context.Context) (detached context.Context, release func()) {
+	ids := scopeIDs(ctx)
+	for _, id := range ids {
+		acquireLease(id)
+	}
+
+	detached = context.WithoutCancel(ctx)
+	if _, ok := ids[0].(context.Context); ok {
+		// scope keyed by context is looked up with the context it was created with
+		detached = context.WithValue(detached, detachedKey{}, ids[0])
+	}
+
+	var once sync.Once
+
+	return detached, func() {
+		once.Do(func() {
+			for _, id := range ids {
+				releaseLease(id)
+			}
+		})
+	}
+}
+
+// Runs fn in new goroutine with context detached from ctx,
+// PerContext scope of ctx is cleaned up only after fn returns.
+func Go(ctx context.Context, fn func(ctx context.Context)) {
+	detached, release := Detach(ctx)
+
+	go func() {
+		defer release()
+
+		fn(detached)
+	}()
+}
+
+// Returns identities of scope of ctx and scopes it is nested in.
+func scopeIDs(ctx context.Context) []any {
+	if s, ok := ctx.Value(explicitScopeKey{}).(*explicitScope); ok {
+		var ids []any
+		for ; s != nil; s = s.parent {
+			ids = append(ids, s)
+		}
+
+		return ids
+	}
+
+	return []any{scopeContext(ctx)}
+}
+
+// Returns context scope of ctx is keyed by.
+func scopeContext(ctx context.Context) context.Context {
+	if origin, ok := ctx.Value(detachedKey{}).(context.Context); ok {
+		return origin
+	}
+
+	return ctx
+}
+
+func acquireLease(id any) {
+	for {
+		val, _ := leases.LoadOrStore(id, &scopeLease{})
+		lease := val.(*scopeLease)
+
+		lease.mu.Lock()
+		if !lease.removed {
+			lease.count++
+			lease.mu.Unlock()
+
+			return
+		}
+
+		// lease was returned while it was loaded
+		lease.mu.Unlock()
+	}
+}
+
+func releaseLease(id any) {
+	val, ok := leases.Load(id)
+	if !ok {
+		return
+	}
+
+	lease := val.(*scopeLease)
+
+	lease.mu.Lock()
+	lease.count--
+	if lease.count > 0 {
+		lease.mu.Unlock()
+		return
+	}
+
+	deferred := lease.deferred
+	lease.removed = true
+	leases.CompareAndDelete(id, lease)
+	lease.mu.Unlock()
+
+	for _, fn := range deferred {
+		fn()
+	}
+}
+
+// Calls fn when all leases of scope are returned, or immediately if scope is not leased.
+func afterLeases(id any, fn func()) {
+	if val, ok := leases.Load(id); ok {
+		lease := val.(*scopeLease)
+
+		lease.mu.Lock()
+		if !lease.removed {
+			lease.deferred = append(lease.deferred, fn)
+			lease.mu.Unlock()
+
+			return
+		}
+
+		lease.mu.Unlock()
+	}
+
+	fn()
+}
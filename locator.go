@@ -1,12 +1,17 @@
 package tinysl
 
 import (
+	"cmp"
 	"context"
+	"errors"
 	"fmt"
+	"maps"
 	"os"
 	"os/signal"
 	"reflect"
 	"runtime/debug"
+	"slices"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"syscall"
@@ -21,25 +26,51 @@ var reflectValuesPool = sync.Pool{
 
 type locatorRecord struct {
 	dependencies []*locatorRecord
+	// Keys dependencies are requested with, which are keys of aliases and inferred bindings, if they are bound to them.
+	dependencyKeys []serviceKey
 	record
 }
 
-func newLocator(ctx context.Context, constructorsByType map[string]*locatorRecord, numS, numP int32) ServiceLocator {
+// Returns key dependency i is requested with, so that it is resolved with override of that key.
+func (r *locatorRecord) dependencyKey(i int) serviceKey {
+	if i < len(r.dependencyKeys) {
+		return r.dependencyKeys[i]
+	}
+
+	return r.dependencies[i].key()
+}
+
+func newLocator(
+	ctx context.Context,
+	constructorsByType map[serviceKey]*locatorRecord,
+	records []*locatorRecord,
+	inferred []serviceKey,
+	resolveByAssignability bool,
+) *locator {
 	ctx, cancel := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM, syscall.SIGINT)
 	singletonsCleanupCh := make(chan cleanupNodeUpdate)
 
-	singletons := make([]*locatorRecord, numS)
-	perContexts := make([]*locatorRecord, numP)
+	singletons := make([]*locatorRecord, 0)
+	perContexts := make([]*locatorRecord, 0)
+	factories := make([]*locatorRecord, 0)
+
+	for _, rec := range records {
+		if rec.args != nil && !rec.private {
+			factories = append(factories, rec)
+		}
 
-	for _, rec := range constructorsByType {
 		switch rec.lifetime {
 		case Singleton:
-			singletons[rec.id] = rec
+			rec.id = int32(len(singletons))
+			singletons = append(singletons, rec)
 		case PerContext:
-			perContexts[rec.id] = rec
+			rec.id = int32(len(perContexts))
+			perContexts = append(perContexts, rec)
 		}
 	}
 
+	numS, numP := int32(len(singletons)), int32(len(perContexts))
+
 	go singletonCleanupWorker(ctx, cancel, buildCleanupNodes(singletons), singletonsCleanupCh)
 
 	cleanupNodeBuilder := func() *cleanupNode {
@@ -51,31 +82,77 @@ func newLocator(ctx context.Context, constructorsByType map[string]*locatorRecor
 		singletonsServices[i] = &serviceScope{}
 	}
 
+	// interfaces that services depend on are already inferred by ServiceLocator(),
+	// other interfaces are resolved by the same services on first request
+	var implementations map[serviceKey]*locatorRecord
+	if resolveByAssignability {
+		implementations = make(map[serviceKey]*locatorRecord)
+		for key, record := range constructorsByType {
+			if key == record.key() {
+				implementations[key] = record
+			}
+		}
+	}
+
 	return &locator{
+		ctx:                 ctx,
 		constructorsByType:  constructorsByType,
+		factories:           factories,
+		inferred:            inferred,
+		implementations:     implementations,
 		perContext:          newContextInstances(numP, cleanupNodeBuilder),
 		singletonsCleanupCh: singletonsCleanupCh,
 		singletons:          singletonsServices,
 	}
 }
 
+// Result of resolving interface by assignability.
+type assignableLookup struct {
+	record *locatorRecord
+	err    error
+}
+
 type locator struct {
+	// context Singletons are cleaned up with
+	ctx                 context.Context
 	err                 atomic.Pointer[error]
 	perContext          *contextInstances
-	constructorsByType  map[string]*locatorRecord
+	constructorsByType  map[serviceKey]*locatorRecord
+	factories           []*locatorRecord
+	contextual          []*locatorRecord
+	inferred            []serviceKey
 	singletonsCleanupCh chan<- cleanupNodeUpdate
 	singletons          []*serviceScope
+	parent              ServiceLocator
+	// services interfaces are resolved with, if ResolveByAssignability option is used
+	implementations map[serviceKey]*locatorRecord
+	// interfaces resolved by assignability on first request
+	assignable sync.Map
 }
 
-func (l *locator) EnsureAvailable(serviceName string) {
-	for key := range l.constructorsByType {
-		if key == serviceName {
-			return
-		}
+func (l *locator) NewChild(opts ...ContainerOption) Container {
+	// Singletons of child may depend on Singletons of parent, so they are cleaned up together by default
+	conf := ContainerConfiguration{
+		Ctx: l.ctx,
 	}
 
-	err := newConstructorNotFoundError(serviceName)
-	l.err.Store(&err)
+	for _, opt := range opts {
+		opt(&conf)
+	}
+
+	conf.parent = l
+
+	return newContainer(conf)
+}
+
+func (l *locator) EnsureAvailable(serviceType reflect.Type) {
+	l.EnsureAvailableNamed(serviceType, "")
+}
+
+func (l *locator) EnsureAvailableNamed(serviceType reflect.Type, name string) {
+	if _, err := l.lookupDependency(serviceKey{serviceType: serviceType, name: name}); err != nil {
+		l.err.Store(&err)
+	}
 }
 
 func (l *locator) Err() error {
@@ -85,44 +162,229 @@ func (l *locator) Err() error {
 	return nil
 }
 
-func (l *locator) Get(ctx context.Context, serviceName string) (service any, err error) {
-	record, ok := l.constructorsByType[serviceName]
+func (l *locator) Get(ctx context.Context, serviceType reflect.Type) (any, error) {
+	return l.GetNamed(ctx, serviceType, "")
+}
+
+func (l *locator) GetNamed(ctx context.Context, serviceType reflect.Type, name string) (any, error) {
+	key := serviceKey{serviceType: serviceType, name: name}
+	if record, ok := l.constructorsByType[key]; ok && record.args == nil {
+		return l.resolve(ctx, key, record)
+	}
+
+	record, err := l.lookupDependency(key)
+	if notFound := new(ConstructorNotFoundError); l.parent != nil && errors.As(err, &notFound) {
+		return l.parent.GetNamed(ctx, serviceType, name)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case serviceType.Implements(optionalInterface):
+		if record == nil {
+			return reflect.Zero(serviceType).Interface(), nil
+		}
+
+		key.serviceType = reflect.Zero(serviceType).Interface().(optionalDependency).serviceType()
+
+		service, err := l.resolve(ctx, key, record)
+		if err != nil {
+			return nil, err
+		}
+
+		return reflect.Zero(serviceType).Interface().(optionalDependency).present(service), nil
+	case serviceType.Implements(lazyInterface), serviceType.Implements(factoryInterface):
+		if serviceType.Implements(lazyInterface) {
+			key.serviceType = reflect.Zero(serviceType).Interface().(lazyDependency).serviceType()
+		}
+
+		return l.getLazy(serviceType, key, record).Interface(), nil
+	default:
+		return l.resolve(ctx, key, record)
+	}
+}
+
+func (l *locator) Bindings() []Binding {
+	bindings := make([]Binding, 0, len(l.constructorsByType))
+	for key, record := range l.constructorsByType {
+		if record.inherited {
+			continue
+		}
+
+		bindings = append(bindings, Binding{
+			ServiceType:      key.serviceType,
+			Implementation:   record.serviceType,
+			Name:             key.name,
+			Lifetime:         record.lifetime,
+			Inferred:         slices.Contains(l.inferred, key),
+			Default:          record.isDefault,
+			OverridesDefault: record.overridesDefault,
+		})
+	}
+
+	// registrations used only to build their consumers are listed for each consumer
+	for _, record := range l.contextual {
+		for _, consumer := range record.consumers {
+			bindings = append(bindings, Binding{
+				ServiceType:      record.serviceType,
+				Implementation:   record.serviceType,
+				Name:             record.name,
+				Lifetime:         record.lifetime,
+				Default:          record.isDefault,
+				OverridesDefault: record.overridesDefault,
+				WhenBuilding:     consumer,
+			})
+		}
+	}
+
+	// services of parent that are registered by child are not inherited
+	if l.parent != nil {
+		for _, binding := range l.parent.Bindings() {
+			record, ok := l.constructorsByType[serviceKey{serviceType: binding.ServiceType, name: binding.Name}]
+			if ok && !record.inherited && binding.WhenBuilding == nil {
+				continue
+			}
+
+			binding.Inherited = true
+			bindings = append(bindings, binding)
+		}
+	}
+
+	slices.SortFunc(bindings, func(a, b Binding) int {
+		return cmp.Or(
+			strings.Compare(a.ServiceType.String(), b.ServiceType.String()),
+			strings.Compare(a.Name, b.Name),
+			strings.Compare(typeName(a.WhenBuilding), typeName(b.WhenBuilding)),
+		)
+	})
+
+	return bindings
+}
+
+// Returns name of type t, empty if there is no type.
+func typeName(t reflect.Type) string {
+	if t == nil {
+		return ""
+	}
+
+	return t.String()
+}
+
+// Interface that is not registered is resolved by the only service implementing it,
+// if ResolveByAssignability option is used. Result is cached, so services are scanned once per interface.
+func (l *locator) lookup(key serviceKey) (*locatorRecord, error) {
+	if record, ok := l.constructorsByType[key]; ok {
+		return record, nil
+	}
+
+	if l.implementations == nil || key.serviceType.Kind() != reflect.Interface {
+		return nil, newConstructorNotFoundError(key.serviceType, key.name)
+	}
+
+	if resolved, ok := l.assignable.Load(key); ok {
+		return resolved.(assignableLookup).record, resolved.(assignableLookup).err
+	}
+
+	var resolved assignableLookup
+	switch candidates := implementations(key, maps.All(l.implementations)); len(candidates) {
+	case 0:
+		resolved.err = newConstructorNotFoundError(key.serviceType, key.name)
+	case 1:
+		resolved.record = candidates[0]
+	default:
+		resolved.err = newAmbiguousDependencyError(key.serviceType, key.name, serviceTypes(candidates))
+	}
+
+	l.assignable.Store(key, resolved)
+
+	return resolved.record, resolved.err
+}
 
-	if !ok {
-		return nil, newConstructorNotFoundError(serviceName)
+// Optional[T], Lazy[T], Provider[T] and Factory[Args, T] are looked up same as constructor dependencies.
+// Absent optional dependency has no record.
+func (l *locator) lookupDependency(key serviceKey) (*locatorRecord, error) {
+	if record, ok := l.constructorsByType[key]; ok && record.args == nil {
+		return record, nil
 	}
 
+	switch t := key.serviceType; {
+	case t.Implements(optionalInterface):
+		key.serviceType = reflect.Zero(t).Interface().(optionalDependency).serviceType()
+
+		record, err := l.lookup(key)
+		if notFound := new(ConstructorNotFoundError); errors.As(err, &notFound) {
+			return nil, nil
+		}
+
+		return record, err
+	case t.Implements(lazyInterface):
+		key.serviceType = reflect.Zero(t).Interface().(lazyDependency).serviceType()
+
+		return l.lookup(key)
+	case t.Implements(factoryInterface):
+		f := reflect.Zero(t).Interface().(factoryDependency)
+		for _, record := range l.factories {
+			if record.serviceType == f.serviceType() && record.name == key.name && record.args == f.argsType() {
+				return record, nil
+			}
+		}
+
+		return nil, newConstructorNotFoundError(t, key.name)
+	default:
+		return l.lookup(key)
+	}
+}
+
+// Resolves record requested with key, which is key of alias or inferred binding, if record is bound to it.
+func (l *locator) resolve(ctx context.Context, key serviceKey, record *locatorRecord) (service any, err error) {
 	defer func() {
 		if rp := recover(); rp != nil {
-			err = newServiceBuilderError(
+			err = newRecordBuilderError(
 				newConstructorError(newRecoveredError(rp, debug.Stack())),
-				record.lifetime,
-				record.typeName,
+				record.record,
 			)
 		}
 	}()
 
-	return l.get(ctx, record, nil)
+	return l.get(ctx, key, record, nil)
 }
 
-func (l *locator) get(ctx context.Context, record *locatorRecord, ctxScope *contextScope) (any, error) {
+func (l *locator) get(ctx context.Context, key serviceKey, record *locatorRecord, ctxScope *contextScope) (any, error) {
+	o, overriddenKey, overridden := overrideOf(ctx, key, record)
+
 	switch record.lifetime {
 	case Singleton:
+		if overridden {
+			return nil, newRecordBuilderError(ErrOverrideSingleton, record.record)
+		}
+
 		return l.getSingleton(ctx, record)
 	case PerContext:
-		return l.getPerContext(ctx, record, ctxScope)
+		return l.getPerContext(ctx, record, ctxScope, o, overriddenKey)
 	case Transient:
+		if overridden {
+			s, _, err := o.build(ctx, record)
+			return s, err
+		}
+
 		s, _, err := l.build(ctx, record, ctxScope)
 		return s, err
 	default:
 		return nil, fmt.Errorf(
 			"broken record %s: %w",
-			record.typeName,
+			record.key().String(),
 			LifetimeUnsupportedError(record.lifetime.String()))
 	}
 }
 
-func (l *locator) build(ctx context.Context, record *locatorRecord, ctxScope *contextScope) (any, Cleanup, error) {
+func (l *locator) build(
+	ctx context.Context,
+	record *locatorRecord,
+	ctxScope *contextScope,
+	factoryArgs ...reflect.Value,
+) (any, Cleanup, error) {
 	constructor := record.constructor
 	fn := reflect.ValueOf(constructor)
 	argsPtr := reflectValuesPool.Get().(*[]reflect.Value)
@@ -133,12 +395,27 @@ func (l *locator) build(ctx context.Context, record *locatorRecord, ctxScope *co
 	}()
 
 	for i, dep := range record.dependencies {
+		if t, ok := record.lazy[i]; ok {
+			args = append(args, l.getLazy(t, record.dependencyKey(i), dep))
+			continue
+		}
+
+		if t, ok := record.optional[i]; ok {
+			arg, err := l.getOptional(ctx, t, record.dependencyKey(i), dep, ctxScope)
+			if err != nil {
+				return nil, nil, err
+			}
+
+			args = append(args, arg)
+			continue
+		}
+
 		if i == 0 && dep.id == -1 {
 			args = append(args, reflect.ValueOf(ctx))
 			continue
 		}
 
-		service, err := l.get(ctx, dep, ctxScope)
+		service, err := l.get(ctx, record.dependencyKey(i), dep, ctxScope)
 		if err != nil {
 			return nil, nil, err
 		}
@@ -146,15 +423,21 @@ func (l *locator) build(ctx context.Context, record *locatorRecord, ctxScope *co
 		args = append(args, reflect.ValueOf(service))
 	}
 
-	values := fn.Call(args)
+	args = append(args, factoryArgs...)
+
+	var values []reflect.Value
+	if record.variadic {
+		values = fn.CallSlice(args)
+	} else {
+		values = fn.Call(args)
+	}
 
 	if record.constructorType == onlyService && len(values) != 1 ||
 		record.constructorType == withError && len(values) != 2 ||
 		record.constructorType == withErrorAndCleanUp && len(values) != 3 {
-		return nil, nil, newServiceBuilderError(
+		return nil, nil, newRecordBuilderError(
 			newConstructorError(newUnexpectedResultError(values)),
-			record.lifetime,
-			record.typeName,
+			record.record,
 		)
 	}
 
@@ -165,10 +448,9 @@ func (l *locator) build(ctx context.Context, record *locatorRecord, ctxScope *co
 	case withError:
 		serviceV, errV := values[0], values[1]
 		if err, ok := (errV.Interface()).(error); ok && err != nil {
-			return nil, nil, newServiceBuilderError(
+			return nil, nil, newRecordBuilderError(
 				newConstructorError(err),
-				record.lifetime,
-				record.typeName,
+				record.record,
 			)
 		}
 
@@ -178,10 +460,9 @@ func (l *locator) build(ctx context.Context, record *locatorRecord, ctxScope *co
 	case withErrorAndCleanUp:
 		serviceV, cleanUpV, errV := values[0], values[1], values[2]
 		if err, ok := (errV.Interface()).(error); ok && err != nil {
-			return nil, nil, newServiceBuilderError(
+			return nil, nil, newRecordBuilderError(
 				newConstructorError(err),
-				record.lifetime,
-				record.typeName,
+				record.record,
 			)
 		}
 
@@ -190,17 +471,91 @@ func (l *locator) build(ctx context.Context, record *locatorRecord, ctxScope *co
 
 		return service, cleanUp.(func()), nil
 	default:
-		return nil, nil, newServiceBuilderError(
+		return nil, nil, newRecordBuilderError(
 			newConstructorUnsupportedError(
 				fn.Type(),
 				record.lifetime,
 			),
-			record.lifetime,
-			record.typeName,
+			record.record,
 		)
 	}
 }
 
+// Lazy dependency is resolved when it is called, with context.Context it is called with.
+func (l *locator) getLazy(t reflect.Type, key serviceKey, record *locatorRecord) reflect.Value {
+	switch lazy := reflect.Zero(t).Interface().(type) {
+	case factoryDependency:
+		return reflect.ValueOf(lazy.builtWith(func(ctx context.Context, args reflect.Value) (any, error) {
+			return l.buildWithArgs(ctx, record, args)
+		}))
+	default:
+		return reflect.ValueOf(lazy.(lazyDependency).resolvedWith(func(ctx context.Context) (any, error) {
+			return l.resolve(ctx, key, record)
+		}))
+	}
+}
+
+// Builds new instance with factory constructor,
+// its cleanup is called together with PerContext cleanup of ctx.
+func (l *locator) buildWithArgs(ctx context.Context, record *locatorRecord, args reflect.Value) (service any, err error) {
+	defer func() {
+		if rp := recover(); rp != nil {
+			err = newRecordBuilderError(
+				newConstructorError(newRecoveredError(rp, debug.Stack())),
+				record.record,
+			)
+		}
+	}()
+
+	if record.constructorType != withErrorAndCleanUp {
+		service, _, err := l.build(ctx, record, nil, args)
+		return service, err
+	}
+
+	if ctx == nil {
+		return nil, newRecordBuilderError(ErrNilContext, record.record)
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, newRecordBuilderError(err, record.record)
+	}
+
+	ctxScope := l.perContext.get(ctx)
+
+	service, cleanUp, err := l.build(ctx, record, ctxScope, args)
+	if err != nil {
+		return nil, err
+	}
+
+	ctxScope.addCleanup(cleanUp)
+
+	return service, nil
+}
+
+// Absent optional dependency has no record and is passed as zero value of t.
+func (l *locator) getOptional(
+	ctx context.Context,
+	t reflect.Type,
+	key serviceKey,
+	record *locatorRecord,
+	ctxScope *contextScope,
+) (reflect.Value, error) {
+	if record == nil {
+		return reflect.Zero(t), nil
+	}
+
+	service, err := l.get(ctx, key, record, ctxScope)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+
+	if optional, ok := reflect.Zero(t).Interface().(optionalDependency); ok {
+		return reflect.ValueOf(optional.present(service)), nil
+	}
+
+	return reflect.ValueOf(service), nil
+}
+
 func (l *locator) getSingleton(ctx context.Context, record *locatorRecord) (any, error) {
 	scope := l.singletons[record.id]
 
@@ -211,7 +566,7 @@ func (l *locator) getSingleton(ctx context.Context, record *locatorRecord) (any,
 		return *scope.value, nil
 	}
 
-	service, cleanUp, err := l.build(ctx, record, nil)
+	service, cleanUp, err := l.build(withoutOverrides(ctx), record, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -235,34 +590,67 @@ func (l *locator) getSingleton(ctx context.Context, record *locatorRecord) (any,
 	return service, nil
 }
 
-func (l *locator) getPerContext(ctx context.Context, record *locatorRecord, ctxScope *contextScope) (any, error) {
+// Overridden service is built with override o instead of its constructor.
+func (l *locator) getPerContext(
+	ctx context.Context,
+	record *locatorRecord,
+	ctxScope *contextScope,
+	o override,
+	overriddenKey serviceKey,
+) (any, error) {
 	if ctx == nil {
-		return nil, newServiceBuilderError(ErrNilContext, record.lifetime, record.typeName)
+		return nil, newRecordBuilderError(ErrNilContext, record.record)
 	}
 
 	if err := ctx.Err(); err != nil {
-		return nil, newServiceBuilderError(err, record.lifetime, record.typeName)
+		return nil, newRecordBuilderError(err, record.record)
 	}
 
 	if ctxScope == nil {
 		ctxScope = l.perContext.get(ctx)
 	}
 
-	ctxScope.services[record.id].lock()
-	defer ctxScope.services[record.id].unlock()
+	// nested scope resolves service from the outermost scope, and it is built with context of that scope
+	for !record.innermost && ctxScope.outer != nil {
+		ctx = ctxScope.outer.contextOf(ctx)
+		ctxScope = ctxScope.outer
+	}
+
+	ctxScope = l.perContext.variant(ctxScope, ctx)
+
+	instance := ctxScope.services[record.id]
+	// override of alias or inferred binding is not shared with service it is bound to
+	aliasOverride := o != nil && overriddenKey != record.key()
+	if aliasOverride {
+		instance = ctxScope.overridden(overriddenKey)
+	}
+
+	instance.lock()
+	defer instance.unlock()
 
-	if !ctxScope.services[record.id].empty() {
-		return *ctxScope.services[record.id].value, nil
+	if !instance.empty() {
+		return *instance.value, nil
+	}
+
+	var (
+		service any
+		cleanUp Cleanup
+		err     error
+	)
+
+	if o != nil {
+		service, cleanUp, err = o.build(ctx, record)
+	} else {
+		service, cleanUp, err = l.build(ctx, record, ctxScope)
 	}
 
-	service, cleanUp, err := l.build(ctx, record, ctxScope)
 	if err != nil {
 		return nil, err
 	}
 
-	ctxScope.services[record.id].value = &service
+	instance.value = &service
 
-	if record.constructorType == withErrorAndCleanUp {
+	if record.constructorType == withErrorAndCleanUp && !aliasOverride {
 		ctxScope.cleanup.updateCleanupNode(record.id, cleanUp)
 	}
 
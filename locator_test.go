@@ -4,9 +4,13 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	htmltemplate "html/template"
+	"reflect"
 	"runtime"
+	"slices"
 	"sync"
 	"sync/atomic"
+	texttemplate "text/template"
 	"time"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -193,6 +197,365 @@ var _ = Describe("ServiceLocator", func() {
 		Expect(s.Name()).To(Equal("twice decorated Bob"))
 	})
 
+	It("should return named services", func() {
+		sl, err := tinysl.
+			Add(tinysl.Singleton, nameServiceConstructor).
+			Add(tinysl.Singleton, func() NameService { return NameProvider("Sam") }, tinysl.WithName("replica")).
+			Add(tinysl.Singleton, heroConstructor).
+			Add(tinysl.Singleton, heroConstructor,
+				tinysl.WithName("replica"),
+				tinysl.WithNamedDependency[NameService]("replica"),
+			).
+			ServiceLocator()
+
+		Expect(err).ShouldNot(HaveOccurred())
+
+		primary, err := tinysl.Get[*Hero](ctx, sl)
+
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(primary.Announce()).To(Equal("Bob is our hero!"))
+
+		replica, err := tinysl.GetNamed[*Hero](ctx, sl, "replica")
+
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(replica.Announce()).To(Equal("Sam is our hero!"))
+
+		_, err = tinysl.GetNamed[*Hero](ctx, sl, "unknown")
+
+		Expect(err).Should(HaveOccurred())
+		Expect(err).Should(BeAssignableToTypeOf(new(tinysl.ConstructorNotFoundError)))
+	})
+
+	It("should resolve dependencies of the same type with names in order of parameters", func() {
+		sl, err := tinysl.
+			Add(tinysl.Singleton, func() NameService { return NameProvider("Bob") }, tinysl.WithName("primary")).
+			Add(tinysl.Singleton, func() NameService { return NameProvider("Sam") }, tinysl.WithName("replica")).
+			Add(tinysl.Singleton, func(primary, replica NameService) *Hero { return &Hero{primary.Name() + " and " + replica.Name()} },
+				tinysl.WithNamedDependency[NameService]("primary"),
+				tinysl.WithNamedDependency[NameService]("replica"),
+			).
+			ServiceLocator()
+
+		Expect(err).ShouldNot(HaveOccurred())
+
+		hero, err := tinysl.Get[*Hero](ctx, sl)
+
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(hero.Announce()).To(Equal("Bob and Sam is our hero!"))
+	})
+
+	It("should fill named dependencies of property fillers", func() {
+		sl, err := tinysl.
+			Add(tinysl.Singleton, nameServiceConstructor).
+			Add(tinysl.Singleton, func() NameService { return NameProvider("Sam") }, tinysl.WithName("replica")).
+			Add(tinysl.Singleton, tinysl.P[ServiceWithPublicFields],
+				tinysl.WithNamedDependency[NameService]("replica"),
+			).
+			ServiceLocator()
+
+		Expect(err).ShouldNot(HaveOccurred())
+
+		s, err := tinysl.Get[*ServiceWithPublicFields](ctx, sl)
+
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(s.Hello()).To(Equal("Hello Sam"))
+	})
+
+	It("should inject group members respecting their lifetimes", func() {
+		sl, err := tinysl.
+			New(tinysl.SilenceUseSingletonWarnings).
+			Add(tinysl.Singleton, func() NameService { return NameProvider("Bob") }, tinysl.AsGroupMember).
+			Add(tinysl.PerContext, func() NameService { return &NameServiceDecorator{NameService: NameProvider("Sam")} }, tinysl.AsGroupMember).
+			Add(tinysl.Transient, func(names ...NameService) *Hero {
+				name := ""
+				for _, n := range names {
+					name += n.Name()
+				}
+
+				return &Hero{name}
+			}).
+			ServiceLocator()
+
+		Expect(err).ShouldNot(HaveOccurred())
+
+		hero, err := tinysl.Get[*Hero](ctx, sl)
+
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(hero.Announce()).To(Equal("Bob Sam is our hero!"))
+
+		ctx1, cancel1 := context.WithCancel(ctx)
+		defer cancel1()
+
+		names1, err := tinysl.GetAll[NameService](ctx, sl)
+
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(names1).To(HaveLen(2))
+
+		names2, err := tinysl.GetAll[NameService](ctx, sl)
+
+		Expect(err).ShouldNot(HaveOccurred())
+
+		names3, err := tinysl.GetAll[NameService](ctx1, sl)
+
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(names2).To(ConsistOf(names1))
+		Expect(names3).To(ContainElement(BeIdenticalTo(NameProvider("Bob"))))
+
+		for _, n := range names3 {
+			if n.Name() == " Sam" {
+				Expect(names1).NotTo(ContainElement(BeIdenticalTo(n)))
+			}
+		}
+	})
+
+	It("should inject named group members", func() {
+		sl, err := tinysl.
+			Add(tinysl.Singleton, func() NameService { return NameProvider("Bob") }, tinysl.AsGroupMember).
+			Add(tinysl.Singleton, func() NameService { return NameProvider("Sam") },
+				tinysl.AsGroupMember,
+				tinysl.WithName("admins"),
+			).
+			Add(tinysl.Singleton, func(names []NameService) *Hero { return &Hero{names[0].Name()} },
+				tinysl.WithNamedDependency[[]NameService]("admins"),
+			).
+			ServiceLocator()
+
+		Expect(err).ShouldNot(HaveOccurred())
+
+		hero, err := tinysl.Get[*Hero](ctx, sl)
+
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(hero.Announce()).To(Equal("Sam is our hero!"))
+	})
+
+	It("should inject map entries respecting their lifetimes", func() {
+		sl, err := tinysl.
+			New(tinysl.SilenceUseSingletonWarnings).
+			Add(tinysl.Singleton, func() NameService { return NameProvider("Bob") }, tinysl.WithMapKey("bob")).
+			Add(tinysl.PerContext, func() NameService { return &NameServiceDecorator{NameService: NameProvider("Sam"), prefix: "Mr."} },
+				tinysl.WithMapKey("sam"),
+			).
+			Add(tinysl.PerContext, func(names map[string]NameService) *Hero { return &Hero{names["sam"].Name()} }).
+			ServiceLocator()
+
+		Expect(err).ShouldNot(HaveOccurred())
+
+		hero, err := tinysl.Get[*Hero](ctx, sl)
+
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(hero.Announce()).To(Equal("Mr. Sam is our hero!"))
+
+		ctx1, cancel1 := context.WithCancel(ctx)
+		defer cancel1()
+
+		names1, err := tinysl.Get[map[string]NameService](ctx, sl)
+
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(names1).To(HaveLen(2))
+
+		names2, err := tinysl.Get[map[string]NameService](ctx1, sl)
+
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(names2["bob"]).To(BeIdenticalTo(names1["bob"]))
+		Expect(names2["sam"]).NotTo(BeIdenticalTo(names1["sam"]))
+	})
+
+	It("should return registered value", func() {
+		hero := &Hero{"Bob"}
+		sl, err := tinysl.
+			AddValue(hero).
+			AddValue(&Hero{"Sam"}, tinysl.WithName("sidekick")).
+			Decorate(tinysl.Singleton, func(h *Hero) *Hero { return &Hero{"Mr. " + h.name} }, tinysl.WithName("sidekick")).
+			ServiceLocator()
+
+		Expect(err).ShouldNot(HaveOccurred())
+
+		h, err := tinysl.Get[*Hero](ctx, sl)
+
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(h).To(BeIdenticalTo(hero))
+
+		h, err = tinysl.GetNamed[*Hero](ctx, sl, "sidekick")
+
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(h.Announce()).To(Equal("Mr. Sam is our hero!"))
+	})
+
+	It("should call value cleanup", func() {
+		appCtx, cancel := context.WithCancel(context.Background())
+		cleaned := make(chan struct{})
+		sl, err := tinysl.
+			New(tinysl.WithSingletonCleanupContext(appCtx)).
+			AddValue(&Hero{"Bob"}, tinysl.WithCleanup(func() { close(cleaned) })).
+			ServiceLocator()
+
+		Expect(err).ShouldNot(HaveOccurred())
+
+		_, err = tinysl.Get[*Hero](ctx, sl)
+
+		Expect(err).ShouldNot(HaveOccurred())
+		time.Sleep(time.Millisecond)
+		cancel()
+		Eventually(cleaned).Should(BeClosed())
+	})
+
+	It("should share the same instance between service and its interfaces", func() {
+		appCtx, cancel := context.WithCancel(context.Background())
+		var cleanups atomic.Int32
+		cleaned := make(chan struct{})
+		sl, err := tinysl.
+			New(tinysl.WithSingletonCleanupContext(appCtx)).
+			Add(tinysl.Singleton, func() (*Impostor, func(), error) {
+				return &Impostor{name: "Bob"}, func() { cleanups.Add(1); close(cleaned) }, nil
+			},
+				tinysl.As[NameService](),
+				tinysl.As[Announcer](),
+			).
+			Add(tinysl.Singleton, heroConstructor).
+			ServiceLocator()
+
+		Expect(err).ShouldNot(HaveOccurred())
+
+		impostor, err := tinysl.Get[*Impostor](ctx, sl)
+
+		Expect(err).ShouldNot(HaveOccurred())
+
+		nameService, err := tinysl.Get[NameService](ctx, sl)
+
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(nameService).To(BeIdenticalTo(impostor))
+
+		announcer, err := tinysl.Get[Announcer](ctx, sl)
+
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(announcer).To(BeIdenticalTo(impostor))
+
+		hero, err := tinysl.Get[*Hero](ctx, sl)
+
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(hero.Announce()).To(Equal("Bob is our hero!"))
+
+		time.Sleep(time.Millisecond)
+		cancel()
+		Eventually(cleaned).Should(BeClosed())
+		Consistently(cleanups.Load).Should(Equal(int32(1)))
+	})
+
+	It("should share decorated instance between service and its interfaces", func() {
+		sl, err := tinysl.
+			Add(tinysl.Singleton, func() *Impostor { return &Impostor{name: "Bob"} }, tinysl.As[NameService]()).
+			Decorate(tinysl.Singleton, func(i *Impostor) *Impostor { return &Impostor{name: "decorated " + i.name} }).
+			ServiceLocator()
+
+		Expect(err).ShouldNot(HaveOccurred())
+
+		impostor, err := tinysl.Get[*Impostor](ctx, sl)
+
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(impostor.Name()).To(Equal("decorated Bob"))
+
+		nameService, err := tinysl.Get[NameService](ctx, sl)
+
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(nameService).To(BeIdenticalTo(impostor))
+	})
+
+	It("should decorate interface with decorated instance of its service", func() {
+		sl, err := tinysl.
+			Add(tinysl.Singleton, func() *Impostor { return &Impostor{name: "Bob"} }, tinysl.As[NameService]()).
+			Decorate(tinysl.Singleton, func(i *Impostor) *Impostor { return &Impostor{name: "decorated " + i.name} }).
+			Decorate(tinysl.Singleton, func(s NameService) NameService { return NameProvider("twice " + s.Name()) }).
+			ServiceLocator()
+
+		Expect(err).ShouldNot(HaveOccurred())
+
+		nameService, err := tinysl.Get[NameService](ctx, sl)
+
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(nameService.Name()).To(Equal("twice decorated Bob"))
+	})
+
+	It("should resolve interfaces by their only implementation", func() {
+		sl, err := tinysl.
+			New(tinysl.ResolveByAssignability).
+			Add(tinysl.Singleton, nameProviderConstructor).
+			Add(tinysl.Singleton, heroConstructor).
+			ServiceLocator()
+
+		Expect(err).ShouldNot(HaveOccurred())
+
+		nameService, err := tinysl.Get[NameService](ctx, sl)
+
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(nameService.Name()).To(Equal("Bob"))
+
+		announcer, err := tinysl.Get[Announcer](ctx, sl)
+
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(announcer.Announce()).To(Equal("Bob is our hero!"))
+
+		Expect(sl.Bindings()).To(Equal([]tinysl.Binding{
+			{
+				ServiceType:    reflect.TypeOf(new(Hero)),
+				Implementation: reflect.TypeOf(new(Hero)),
+				Lifetime:       tinysl.Singleton,
+			},
+			{
+				ServiceType:    reflect.TypeOf(NameProvider("")),
+				Implementation: reflect.TypeOf(NameProvider("")),
+				Lifetime:       tinysl.Singleton,
+			},
+			{
+				ServiceType:    reflect.TypeOf(new(NameService)).Elem(),
+				Implementation: reflect.TypeOf(NameProvider("")),
+				Lifetime:       tinysl.Singleton,
+				Inferred:       true,
+			},
+		}))
+		Expect(sl.Bindings()[2].String()).To(Equal(
+			"tinysl_test.NameService -> tinysl_test.NameProvider (Singleton, inferred)",
+		))
+	})
+
+	It("should return error if requested interface has several implementations", func() {
+		sl, err := tinysl.
+			New(tinysl.ResolveByAssignability).
+			Add(tinysl.Singleton, nameServiceConstructor).
+			Add(tinysl.Singleton, heroConstructor).
+			Add(tinysl.Singleton, func() *Impostor { return &Impostor{name: "Bob"} }).
+			ServiceLocator()
+
+		Expect(err).ShouldNot(HaveOccurred())
+
+		_, err = tinysl.Get[Announcer](ctx, sl)
+
+		Expect(err).Should(HaveOccurred())
+		Expect(err).Should(BeAssignableToTypeOf(new(tinysl.AmbiguousDependencyError)))
+
+		sl.EnsureAvailable(reflect.TypeOf(new(Announcer)).Elem())
+
+		Expect(sl.Err()).Should(BeAssignableToTypeOf(new(tinysl.AmbiguousDependencyError)))
+	})
+
+	It("should resolve types with the same name from different packages", func() {
+		sl, err := tinysl.
+			Add(tinysl.Singleton, func() *texttemplate.Template { return texttemplate.New("text") }).
+			Add(tinysl.Singleton, func() *htmltemplate.Template { return htmltemplate.New("html") }).
+			ServiceLocator()
+
+		Expect(err).ShouldNot(HaveOccurred())
+
+		text, err := tinysl.Get[*texttemplate.Template](ctx, sl)
+
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(text.Name()).To(Equal("text"))
+
+		html, err := tinysl.Get[*htmltemplate.Template](ctx, sl)
+
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(html.Name()).To(Equal("html"))
+	})
+
 	It("should return error on missing constructor", func() {
 		sl, err := tinysl.
 			New(tinysl.SilenceUseSingletonWarnings).
@@ -463,57 +826,223 @@ var _ = Describe("ServiceLocator", func() {
 		Expect(service.Name()).To(Equal("Bob"))
 	})
 
-	It("should work with P", func() {
+	It("should pass absent optional dependencies as zero values", func() {
 		sl, err := tinysl.
-			New(tinysl.SilenceUseSingletonWarnings).
-			Add(tinysl.PerContext, nameServiceConstructor).
-			Add(tinysl.PerContext, tinysl.P[ServiceWithPublicFields]).
+			Add(tinysl.Singleton, func(nameService tinysl.Optional[NameService]) *Hero {
+				_, ok := nameService.Get()
+				Expect(ok).To(BeFalse())
+
+				return &Hero{nameService.OrElse(NameProvider("Sam")).Name()}
+			}).
+			Add(tinysl.Singleton, tinysl.P[ServiceWithOptionalFields]).
 			ServiceLocator()
 
 		Expect(err).ShouldNot(HaveOccurred())
 
-		service, err := tinysl.Get[*ServiceWithPublicFields](ctx, sl)
+		hero, err := tinysl.Get[*Hero](ctx, sl)
 
 		Expect(err).ShouldNot(HaveOccurred())
+		Expect(hero.Announce()).To(Equal("Sam is our hero!"))
 
-		Expect(service.SomeProperty()).To(BeEmpty())
-		Expect(service.Name()).To(Equal("Bob"))
+		service, err := tinysl.Get[*ServiceWithOptionalFields](ctx, sl)
+
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(service.Dependency).To(BeNil())
+		Expect(service.Hero).To(BeIdenticalTo(hero))
 	})
 
-	It("should work with I", func() {
+	It("should pass registered optional dependencies", func() {
 		sl, err := tinysl.
-			New(tinysl.SilenceUseSingletonWarnings).
-			Add(tinysl.PerContext, nameServiceConstructor).
-			Add(tinysl.PerContext, tinysl.I[HelloService, ServiceWithPublicFields]).
+			Add(tinysl.Singleton, nameServiceConstructor).
+			Add(tinysl.Singleton, func(nameService tinysl.Optional[NameService]) *Hero {
+				return &Hero{nameService.OrElse(NameProvider("Sam")).Name()}
+			}).
+			Add(tinysl.Singleton, tinysl.T[ServiceWithOptionalFields]).
 			ServiceLocator()
 
 		Expect(err).ShouldNot(HaveOccurred())
 
-		service, err := tinysl.Get[HelloService](ctx, sl)
+		hero, err := tinysl.Get[*Hero](ctx, sl)
 
 		Expect(err).ShouldNot(HaveOccurred())
+		Expect(hero.Announce()).To(Equal("Bob is our hero!"))
 
-		Expect(service.Hello()).To(Equal("Hello Bob"))
-		Expect(service.(*ServiceWithPublicFields).SomeProperty()).To(BeEmpty())
+		service, err := tinysl.Get[ServiceWithOptionalFields](ctx, sl)
+
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(service.Dependency.Name()).To(Equal("Bob"))
+		Expect(service.Hero).To(BeIdenticalTo(hero))
 	})
 
-	It("should use cleanup function for PerContext", func() {
-		cleaned := make(chan struct{})
+	It("should resolve Lazy and Provider dependencies on demand", func() {
+		var (
+			built    atomic.Int32
+			lazy     tinysl.Lazy[NameService]
+			provider tinysl.Provider[NameService]
+		)
+
 		sl, err := tinysl.
-			New(tinysl.SilenceUseSingletonWarnings).
-			Add(tinysl.PerContext, nameServiceConstructor).
-			Add(tinysl.PerContext, heroConstructorWithCleanup(func() { close(cleaned) })).
+			Add(tinysl.PerContext, func() NameService { built.Add(1); return NameProvider("Bob") }).
+			Add(tinysl.Singleton, func(l tinysl.Lazy[NameService], p tinysl.Provider[NameService]) *Hero {
+				lazy, provider = l, p
+				return &Hero{"Sam"}
+			}).
 			ServiceLocator()
+
 		Expect(err).ShouldNot(HaveOccurred())
 
-		ctx, cancel := context.WithCancel(ctx)
 		_, err = tinysl.Get[*Hero](ctx, sl)
 
 		Expect(err).ShouldNot(HaveOccurred())
+		Expect(built.Load()).To(Equal(int32(0)))
 
-		time.Sleep(time.Millisecond)
-		cancel()
-		Eventually(cleaned).Should(BeClosed())
+		nameService := lazy(ctx)
+
+		Expect(nameService.Name()).To(Equal("Bob"))
+
+		nameServiceFromProvider, err := provider(ctx)
+
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(nameServiceFromProvider).To(Equal(nameService))
+		Expect(built.Load()).To(Equal(int32(1)))
+
+		otherCtx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		_, err = provider(otherCtx)
+
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(built.Load()).To(Equal(int32(2)))
+	})
+
+	It("should resolve circular dependency through Lazy", func() {
+		var lazy tinysl.Lazy[*Hero]
+
+		sl, err := tinysl.
+			Add(tinysl.Singleton, func(l tinysl.Lazy[*Hero]) NameService {
+				lazy = l
+				return NameProvider("Bob")
+			}).
+			Add(tinysl.Singleton, heroConstructor).
+			ServiceLocator()
+
+		Expect(err).ShouldNot(HaveOccurred())
+
+		hero, err := tinysl.Get[*Hero](ctx, sl)
+
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(lazy(ctx)).To(BeIdenticalTo(hero))
+	})
+
+	It("should return error from Provider", func() {
+		var provider tinysl.Provider[NameService]
+
+		sl, err := tinysl.
+			Add(tinysl.Transient, func() (NameService, error) { return nil, errors.New("no name") }).
+			Add(tinysl.Transient, func(p tinysl.Provider[NameService]) *Hero {
+				provider = p
+				return &Hero{"Sam"}
+			}).
+			ServiceLocator()
+
+		Expect(err).ShouldNot(HaveOccurred())
+
+		_, err = tinysl.Get[*Hero](ctx, sl)
+
+		Expect(err).ShouldNot(HaveOccurred())
+
+		_, err = provider(ctx)
+
+		Expect(err).Should(HaveOccurred())
+		Expect(errors.Unwrap(err)).Should(BeAssignableToTypeOf(new(tinysl.ConstructorError)))
+	})
+
+	It("should build parameter objects", func() {
+		sl, err := tinysl.
+			Add(tinysl.PerContext, heroWithParamsConstructor).
+			Add(tinysl.Singleton, nameServiceConstructor, tinysl.WithName("hero")).
+			Add(tinysl.Singleton, func() Announcer { return &Hero{"Sam"} }, tinysl.AsGroupMember).
+			Add(tinysl.Singleton, func() Announcer { return &Hero{"Ann"} }, tinysl.AsGroupMember).
+			ServiceLocator()
+
+		Expect(err).ShouldNot(HaveOccurred())
+
+		hero, err := tinysl.Get[*Hero](ctx, sl)
+
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(hero.Announce()).To(Equal("Bob, Sam is our hero!, Ann is our hero! is our hero!"))
+	})
+
+	It("should fill tagged fields with P", func() {
+		sl, err := tinysl.
+			Add(tinysl.Singleton, tinysl.P[ServiceWithTags]).
+			Add(tinysl.Singleton, nameServiceConstructor, tinysl.WithName("hero")).
+			Add(tinysl.Singleton, func() Announcer { return &Hero{"Sam"} }, tinysl.WithName("heroes"), tinysl.AsGroupMember).
+			ServiceLocator()
+
+		Expect(err).ShouldNot(HaveOccurred())
+
+		service, err := tinysl.Get[*ServiceWithTags](ctx, sl)
+
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(service.Hero).To(BeNil())
+		Expect(service.NameService.Name()).To(Equal("Bob"))
+		Expect(service.Announcers).To(HaveLen(1))
+		Expect(service.Timeout).To(BeZero())
+		Expect(service.Name).To(BeEmpty())
+	})
+
+	It("should work with P", func() {
+		sl, err := tinysl.
+			New(tinysl.SilenceUseSingletonWarnings).
+			Add(tinysl.PerContext, nameServiceConstructor).
+			Add(tinysl.PerContext, tinysl.P[ServiceWithPublicFields]).
+			ServiceLocator()
+
+		Expect(err).ShouldNot(HaveOccurred())
+
+		service, err := tinysl.Get[*ServiceWithPublicFields](ctx, sl)
+
+		Expect(err).ShouldNot(HaveOccurred())
+
+		Expect(service.SomeProperty()).To(BeEmpty())
+		Expect(service.Name()).To(Equal("Bob"))
+	})
+
+	It("should work with I", func() {
+		sl, err := tinysl.
+			New(tinysl.SilenceUseSingletonWarnings).
+			Add(tinysl.PerContext, nameServiceConstructor).
+			Add(tinysl.PerContext, tinysl.I[HelloService, ServiceWithPublicFields]).
+			ServiceLocator()
+
+		Expect(err).ShouldNot(HaveOccurred())
+
+		service, err := tinysl.Get[HelloService](ctx, sl)
+
+		Expect(err).ShouldNot(HaveOccurred())
+
+		Expect(service.Hello()).To(Equal("Hello Bob"))
+		Expect(service.(*ServiceWithPublicFields).SomeProperty()).To(BeEmpty())
+	})
+
+	It("should use cleanup function for PerContext", func() {
+		cleaned := make(chan struct{})
+		sl, err := tinysl.
+			New(tinysl.SilenceUseSingletonWarnings).
+			Add(tinysl.PerContext, nameServiceConstructor).
+			Add(tinysl.PerContext, heroConstructorWithCleanup(func() { close(cleaned) })).
+			ServiceLocator()
+		Expect(err).ShouldNot(HaveOccurred())
+
+		ctx, cancel := context.WithCancel(ctx)
+		_, err = tinysl.Get[*Hero](ctx, sl)
+
+		Expect(err).ShouldNot(HaveOccurred())
+
+		time.Sleep(time.Millisecond)
+		cancel()
+		Eventually(cleaned).Should(BeClosed())
 	})
 
 	It("should use cleanup function for Singleton", func() {
@@ -619,6 +1148,798 @@ var _ = Describe("ServiceLocator", func() {
 		Expect(first.Before(last)).To(BeTrue())
 	})
 
+	It("should resolve only services exported by modules", func() {
+		sl, err := tinysl.
+			AddModule(tinysl.NewModule("heroes", func(c tinysl.Container) {
+				c.Add(tinysl.Singleton, nameServiceConstructor).
+					Add(tinysl.Singleton, heroConstructor).
+					Add(tinysl.Singleton, func() Announcer { return &Hero{"Sam"} }, tinysl.AsGroupMember).
+					AddFactory(func(args HeroArgs) *Impostor { return &Impostor{name: args.Title} })
+			}, tinysl.Export[*Hero](), tinysl.Export[Announcer]())).
+			ServiceLocator()
+
+		Expect(err).ShouldNot(HaveOccurred())
+
+		hero, err := tinysl.Get[*Hero](ctx, sl)
+
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(hero.Announce()).To(Equal("Bob is our hero!"))
+
+		announcers, err := tinysl.GetAll[Announcer](ctx, sl)
+
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(announcers).To(HaveLen(1))
+
+		_, err = tinysl.Get[NameService](ctx, sl)
+
+		Expect(err).Should(HaveOccurred())
+		Expect(err).Should(BeAssignableToTypeOf(new(tinysl.ConstructorNotFoundError)))
+
+		_, err = tinysl.Get[tinysl.Factory[HeroArgs, *Impostor]](ctx, sl)
+
+		Expect(err).Should(HaveOccurred())
+		Expect(sl.Bindings()).To(HaveLen(2))
+	})
+
+	It("should use default registrations only if there is no regular registration", func() {
+		c := tinysl.
+			Add(tinysl.Singleton, heroConstructor).
+			Add(tinysl.Singleton, func() NameService { return NameProvider("Default") }, tinysl.AsDefault).
+			AddValue(NameProvider("Bob"), tinysl.AsDefault)
+
+		sl, err := c.ServiceLocator()
+
+		Expect(err).ShouldNot(HaveOccurred())
+
+		hero, err := tinysl.Get[*Hero](ctx, sl)
+
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(hero.Announce()).To(Equal("Default is our hero!"))
+		Expect(sl.Bindings()).To(ContainElement(tinysl.Binding{
+			ServiceType:    reflect.TypeOf(new(NameService)).Elem(),
+			Implementation: reflect.TypeOf(new(NameService)).Elem(),
+			Lifetime:       tinysl.Singleton,
+			Default:        true,
+		}))
+
+		_, err = c.
+			Add(tinysl.PerContext, nameServiceConstructor).
+			ServiceLocator()
+
+		Expect(err).Should(HaveOccurred())
+		Expect(errors.Unwrap(err)).Should(BeAssignableToTypeOf(new(tinysl.ScopeHierarchyError)))
+
+		sl, err = tinysl.
+			New(tinysl.SilenceUseSingletonWarnings).
+			Add(tinysl.Singleton, heroConstructor).
+			Add(tinysl.Singleton, nameServiceConstructor).
+			Add(tinysl.Singleton, func() NameService { return NameProvider("Default") }, tinysl.AsDefault).
+			ServiceLocator()
+
+		Expect(err).ShouldNot(HaveOccurred())
+
+		hero, err = tinysl.Get[*Hero](ctx, sl)
+
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(hero.Announce()).To(Equal("Bob is our hero!"))
+		Expect(sl.Bindings()[1].String()).To(Equal("tinysl_test.NameService (Singleton, overrides default)"))
+	})
+
+	It("should build ServiceLocators with default registrations concurrently", func() {
+		c := tinysl.
+			Add(tinysl.Singleton, heroConstructor).
+			Add(tinysl.Singleton, nameServiceConstructor).
+			Add(tinysl.Singleton, func() NameService { return NameProvider("Default") }, tinysl.AsDefault).
+			Add(tinysl.Singleton, impostorConstructor, tinysl.AsDefault)
+
+		var wg sync.WaitGroup
+		for range 4 {
+			wg.Add(1)
+			go func() {
+				defer GinkgoRecover()
+				defer wg.Done()
+
+				sl, err := c.ServiceLocator()
+
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(sl.Bindings()).To(ContainElements(
+					HaveField("OverridesDefault", true),
+					HaveField("Default", true),
+				))
+			}()
+		}
+
+		wg.Wait()
+	})
+
+	It("should resolve provider methods with their lifetimes", func() {
+		sl, err := tinysl.
+			AddProvider(HeroProviders{name: "Bob"}).
+			ServiceLocator()
+
+		Expect(err).ShouldNot(HaveOccurred())
+
+		hero, err := tinysl.Get[*Hero](ctx, sl)
+
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(hero.Announce()).To(Equal("Bob is our hero!"))
+		Expect(tinysl.MustGet[*Hero](ctx, sl)).To(BeIdenticalTo(hero))
+
+		impostor, err := tinysl.Get[*Impostor](ctx, sl)
+
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(impostor.Name()).To(Equal("Bob"))
+		Expect(tinysl.MustGet[*Impostor](ctx, sl)).NotTo(BeIdenticalTo(impostor))
+
+		Expect(sl.Bindings()).To(ContainElements(
+			HaveField("Lifetime", tinysl.Singleton),
+			HaveField("Lifetime", tinysl.PerContext),
+			HaveField("Lifetime", tinysl.Transient),
+		))
+
+		sl, err = tinysl.
+			AddProvider(AnnotatedHeroProviders{}).
+			ServiceLocator()
+
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(sl.Bindings()).To(ConsistOf(HaveField("Lifetime", tinysl.PerContext)))
+	})
+
+	It("should resolve dependency with registration used when building its consumer", func() {
+		sl, err := tinysl.
+			New(tinysl.SilenceUseSingletonWarnings).
+			Add(tinysl.Singleton, nameServiceConstructor).
+			Add(tinysl.Singleton, func() NameService { return NameProvider("Sam") }, tinysl.WhenBuilding[*Impostor]()).
+			Add(tinysl.Singleton, heroConstructor).
+			Add(tinysl.Transient, impostorConstructor).
+			ServiceLocator()
+
+		Expect(err).ShouldNot(HaveOccurred())
+
+		impostor, err := tinysl.Get[*Impostor](ctx, sl)
+
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(impostor.Name()).To(Equal("Sam"))
+		Expect(impostor.hero.Announce()).To(Equal("Bob is our hero!"))
+
+		nameService, err := tinysl.Get[NameService](ctx, sl)
+
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(nameService.Name()).To(Equal("Bob"))
+
+		Expect(sl.Bindings()).To(HaveLen(4))
+		Expect(sl.Bindings()[3].String()).To(Equal(
+			"tinysl_test.NameService (Singleton, when building *tinysl_test.Impostor)",
+		))
+	})
+
+	It("should resolve dependency registered only when building its consumer", func() {
+		sl, err := tinysl.
+			Add(tinysl.Singleton, func() NameService { return NameProvider("Sam") },
+				tinysl.WhenBuilding[*Hero](),
+				tinysl.WhenBuilding[*Impostor](),
+			).
+			Add(tinysl.Singleton, heroConstructor).
+			ServiceLocator()
+
+		Expect(err).ShouldNot(HaveOccurred())
+
+		hero, err := tinysl.Get[*Hero](ctx, sl)
+
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(hero.Announce()).To(Equal("Sam is our hero!"))
+
+		_, err = tinysl.Get[NameService](ctx, sl)
+
+		Expect(err).Should(HaveOccurred())
+		Expect(err).Should(BeAssignableToTypeOf(new(tinysl.ConstructorNotFoundError)))
+		Expect(sl.Bindings()).To(ContainElement(HaveField("WhenBuilding", reflect.TypeOf(new(Impostor)))))
+	})
+
+	It("should share PerContext services in contexts derived from explicit scope", func() {
+		cleaned := make(chan struct{})
+		sl, err := tinysl.
+			Add(tinysl.PerContext, nameServiceConstructor).
+			Add(tinysl.PerContext, heroConstructorWithCleanup(func() { close(cleaned) })).
+			ServiceLocator()
+
+		Expect(err).ShouldNot(HaveOccurred())
+
+		scoped, end := tinysl.NewScope(context.TODO())
+		hero, err := tinysl.Get[*Hero](scoped, sl)
+
+		Expect(err).ShouldNot(HaveOccurred())
+
+		withTimeout, cancel := context.WithTimeout(scoped, time.Second)
+		defer cancel()
+
+		Expect(tinysl.MustGet[*Hero](withTimeout, sl)).To(BeIdenticalTo(hero))
+
+		cancel()
+
+		Consistently(cleaned, time.Millisecond*100).ShouldNot(BeClosed())
+		Expect(tinysl.MustGet[*Hero](scoped, sl)).To(BeIdenticalTo(hero))
+
+		otherScope, endOther := tinysl.NewScope(context.TODO())
+		defer endOther()
+
+		Expect(tinysl.MustGet[*Hero](otherScope, sl)).NotTo(BeIdenticalTo(hero))
+
+		end()
+
+		Eventually(cleaned).Should(BeClosed())
+
+		_, err = tinysl.Get[*Hero](scoped, sl)
+
+		Expect(err).Should(HaveOccurred())
+		Expect(errors.Unwrap(err)).To(MatchError(context.Canceled))
+	})
+
+	It("should not share PerContext services between contexts of explicit scope with other overrides or scoped values", func() {
+		sl, err := tinysl.
+			Add(tinysl.PerContext, tinysl.Scoped[NameService]).
+			Add(tinysl.PerContext, heroConstructor).
+			ServiceLocator()
+
+		Expect(err).ShouldNot(HaveOccurred())
+
+		scoped, end := tinysl.NewScope(tinysl.WithScopedValue[NameService](context.TODO(), NameProvider("Bob")))
+		defer end()
+
+		hero, err := tinysl.Get[*Hero](scoped, sl)
+
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(hero.Announce()).To(Equal("Bob is our hero!"))
+
+		overridden := tinysl.Override[NameService](scoped, NameProvider("Sam"))
+		overriddenHero := tinysl.MustGet[*Hero](overridden, sl)
+
+		Expect(overriddenHero.Announce()).To(Equal("Sam is our hero!"))
+		Expect(tinysl.MustGet[*Hero](context.WithoutCancel(overridden), sl)).To(BeIdenticalTo(overriddenHero))
+
+		seeded := tinysl.WithScopedValue[NameService](scoped, NameProvider("Tom"))
+
+		Expect(tinysl.MustGet[*Hero](seeded, sl).Announce()).To(Equal("Tom is our hero!"))
+		Expect(tinysl.MustGet[*Hero](scoped, sl)).To(BeIdenticalTo(hero))
+	})
+
+	It("should resolve services from nested scopes and clean them up before their parent", func() {
+		var order []string
+		var mu sync.Mutex
+		track := func(name string) func() {
+			return func() {
+				mu.Lock()
+				defer mu.Unlock()
+
+				order = append(order, name)
+			}
+		}
+
+		sl, err := tinysl.
+			Add(tinysl.PerContext, nameServiceConstructorWithCleanup(track("connection"))).
+			Add(tinysl.PerContext, heroConstructorWithCleanup(track("message")), tinysl.PerInnermostScope).
+			ServiceLocator()
+
+		Expect(err).ShouldNot(HaveOccurred())
+
+		connection, endConnection := tinysl.NewScope(context.TODO())
+		first, endFirst := tinysl.NewScope(connection)
+		second, endSecond := tinysl.NewScope(connection)
+
+		defer endSecond()
+
+		nameService := tinysl.MustGet[NameService](connection, sl)
+		hero := tinysl.MustGet[*Hero](first, sl)
+
+		Expect(tinysl.MustGet[NameService](first, sl)).To(Equal(nameService))
+		Expect(tinysl.MustGet[NameService](second, sl)).To(Equal(nameService))
+		Expect(tinysl.MustGet[*Hero](first, sl)).To(BeIdenticalTo(hero))
+		Expect(tinysl.MustGet[*Hero](second, sl)).NotTo(BeIdenticalTo(hero))
+
+		endFirst()
+
+		Eventually(func() []string {
+			mu.Lock()
+			defer mu.Unlock()
+
+			return slices.Clone(order)
+		}).Should(Equal([]string{"message"}))
+
+		endConnection()
+
+		Eventually(func() []string {
+			mu.Lock()
+			defer mu.Unlock()
+
+			return slices.Clone(order)
+		}).Should(Equal([]string{"message", "message", "connection"}))
+	})
+
+	It("should resolve services of nested scopes from the outermost scope with its context", func() {
+		sl, err := tinysl.
+			Add(tinysl.PerContext, nameServiceConstructor).
+			Add(tinysl.PerContext, tableTimerConstructor).
+			Add(tinysl.PerContext, func(timer *TableTimer) *Hero { return &Hero{timer.nameService.Name()} }, tinysl.PerInnermostScope).
+			ServiceLocator()
+
+		Expect(err).ShouldNot(HaveOccurred())
+
+		app, endApp := tinysl.NewScope(context.TODO())
+		defer endApp()
+
+		connection, endConnection := tinysl.NewScope(app)
+		otherConnection, endOtherConnection := tinysl.NewScope(app)
+		defer endOtherConnection()
+
+		message, endMessage := tinysl.NewScope(connection)
+		otherMessage, endOtherMessage := tinysl.NewScope(otherConnection)
+		defer endOtherMessage()
+
+		timer := tinysl.MustGet[*TableTimer](message, sl)
+
+		Expect(tinysl.MustGet[*TableTimer](app, sl)).To(BeIdenticalTo(timer))
+		Expect(tinysl.MustGet[*TableTimer](connection, sl)).To(BeIdenticalTo(timer))
+		Expect(tinysl.MustGet[*TableTimer](otherMessage, sl)).To(BeIdenticalTo(timer))
+		Expect(tinysl.MustGet[*Hero](message, sl)).NotTo(BeIdenticalTo(tinysl.MustGet[*Hero](otherMessage, sl)))
+
+		endMessage()
+		endConnection()
+
+		Consistently(timer.ctx.Done(), time.Millisecond*100).ShouldNot(BeClosed())
+
+		endApp()
+
+		Eventually(timer.ctx.Done()).Should(BeClosed())
+	})
+
+	It("should clean up explicit scope synchronously when it is ended", func() {
+		cleaned := false
+		sl, err := tinysl.
+			Add(tinysl.PerContext, nameServiceConstructor).
+			Add(tinysl.PerContext, heroConstructorWithCleanup(func() { cleaned = true })).
+			ServiceLocator()
+
+		Expect(err).ShouldNot(HaveOccurred())
+
+		scoped, end := tinysl.NewScope(ctx, tinysl.SyncCleanup)
+
+		_, err = tinysl.Get[*Hero](scoped, sl)
+
+		Expect(err).ShouldNot(HaveOccurred())
+
+		end()
+
+		Expect(cleaned).To(BeTrue())
+		Expect(tinysl.AwaitCleanup(scoped)).To(Succeed())
+	})
+
+	It("should await cleanup of explicit scope and return recovered panics", func() {
+		sl, err := tinysl.
+			Add(tinysl.PerContext, nameServiceConstructorWithCleanup(func() { panic("cleanup failed") })).
+			ServiceLocator()
+
+		Expect(err).ShouldNot(HaveOccurred())
+
+		scoped, end := tinysl.NewScope(ctx)
+
+		_, err = tinysl.Get[NameService](scoped, sl)
+
+		Expect(err).ShouldNot(HaveOccurred())
+
+		end()
+
+		err = tinysl.AwaitCleanup(scoped)
+
+		Expect(err).Should(HaveOccurred())
+
+		recovered := new(tinysl.RecoveredError)
+
+		Expect(errors.As(err, &recovered)).To(BeTrue())
+		Expect(recovered.Panic).To(Equal("cleanup failed"))
+		Expect(tinysl.AwaitCleanup(context.TODO())).To(MatchError(tinysl.ErrAwaitWithoutScope))
+	})
+
+	It("should await cleanup of context scope", func() {
+		var cleaned atomic.Bool
+		sl, err := tinysl.
+			Add(tinysl.PerContext, nameServiceConstructorWithCleanup(func() {
+				time.Sleep(time.Millisecond * 10)
+				cleaned.Store(true)
+			})).
+			ServiceLocator()
+
+		Expect(err).ShouldNot(HaveOccurred())
+
+		requestCtx, cancelRequest := context.WithCancel(ctx)
+
+		_, err = tinysl.Get[NameService](requestCtx, sl)
+
+		Expect(err).ShouldNot(HaveOccurred())
+
+		cancelRequest()
+
+		Expect(tinysl.AwaitCleanup(requestCtx)).To(Succeed())
+		Expect(cleaned.Load()).To(BeTrue())
+	})
+
+	It("should return recovered panics of context scope", func() {
+		sl, err := tinysl.
+			Add(tinysl.PerContext, nameServiceConstructorWithCleanup(func() {
+				time.Sleep(time.Millisecond * 10)
+				panic("cleanup failed")
+			})).
+			ServiceLocator()
+
+		Expect(err).ShouldNot(HaveOccurred())
+
+		requestCtx, cancelRequest := context.WithCancel(ctx)
+
+		_, err = tinysl.Get[NameService](requestCtx, sl)
+
+		Expect(err).ShouldNot(HaveOccurred())
+
+		cancelRequest()
+
+		err = tinysl.AwaitCleanup(requestCtx)
+
+		recovered := new(tinysl.RecoveredError)
+
+		Expect(errors.As(err, &recovered)).To(BeTrue())
+		Expect(recovered.Panic).To(Equal("cleanup failed"))
+	})
+
+	It("should return recovered panics of nested scopes with their parent", func() {
+		sl, err := tinysl.
+			Add(tinysl.PerContext, nameServiceConstructorWithCleanup(func() { panic("cleanup failed") }), tinysl.PerInnermostScope).
+			ServiceLocator()
+
+		Expect(err).ShouldNot(HaveOccurred())
+
+		parent, end := tinysl.NewScope(ctx)
+		nested, endNested := tinysl.NewScope(parent)
+
+		defer endNested()
+
+		_, err = tinysl.Get[NameService](nested, sl)
+
+		Expect(err).ShouldNot(HaveOccurred())
+
+		end()
+
+		err = tinysl.AwaitCleanup(parent)
+
+		recovered := new(tinysl.RecoveredError)
+
+		Expect(errors.As(err, &recovered)).To(BeTrue())
+		Expect(recovered.Panic).To(Equal("cleanup failed"))
+	})
+
+	It("should keep PerContext scope alive for goroutines started with Go", func() {
+		cleaned := make(chan struct{})
+		proceed := make(chan struct{})
+		resolved := make(chan *Hero, 1)
+
+		sl, err := tinysl.
+			Add(tinysl.PerContext, nameServiceConstructor).
+			Add(tinysl.PerContext, heroConstructorWithCleanup(func() { close(cleaned) })).
+			ServiceLocator()
+
+		Expect(err).ShouldNot(HaveOccurred())
+
+		requestCtx, cancel := context.WithCancel(ctx)
+		hero := tinysl.MustGet[*Hero](requestCtx, sl)
+
+		tinysl.Go(requestCtx, func(ctx context.Context) {
+			<-proceed
+			resolved <- tinysl.MustGet[*Hero](ctx, sl)
+		})
+
+		cancel()
+
+		Consistently(cleaned, time.Millisecond*100).ShouldNot(BeClosed())
+
+		close(proceed)
+
+		Eventually(resolved).Should(Receive(BeIdenticalTo(hero)))
+		Eventually(cleaned).Should(BeClosed())
+	})
+
+	It("should carry explicit scope into detached context until lease is returned", func() {
+		cleaned := make(chan struct{})
+		sl, err := tinysl.
+			Add(tinysl.PerContext, nameServiceConstructor).
+			Add(tinysl.PerContext, heroConstructorWithCleanup(func() { close(cleaned) })).
+			ServiceLocator()
+
+		Expect(err).ShouldNot(HaveOccurred())
+
+		scoped, end := tinysl.NewScope(ctx)
+		hero := tinysl.MustGet[*Hero](scoped, sl)
+		detached, release := tinysl.Detach(scoped)
+
+		end()
+
+		Consistently(cleaned, time.Millisecond*100).ShouldNot(BeClosed())
+		Expect(detached.Err()).ShouldNot(HaveOccurred())
+		Expect(tinysl.MustGet[*Hero](detached, sl)).To(BeIdenticalTo(hero))
+
+		release()
+		release()
+
+		Eventually(cleaned).Should(BeClosed())
+	})
+
+	It("should fall back to parent in child ServiceLocator", func() {
+		parent, err := tinysl.
+			New(tinysl.SilenceUseSingletonWarnings).
+			Add(tinysl.Singleton, nameServiceConstructor).
+			Add(tinysl.Singleton, heroConstructor).
+			ServiceLocator()
+
+		Expect(err).ShouldNot(HaveOccurred())
+
+		sl, err := parent.
+			NewChild().
+			Add(tinysl.Transient, impostorConstructor).
+			ServiceLocator()
+
+		Expect(err).ShouldNot(HaveOccurred())
+
+		hero, err := tinysl.Get[*Hero](ctx, sl)
+
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(hero).To(BeIdenticalTo(tinysl.MustGet[*Hero](ctx, parent)))
+
+		impostor, err := tinysl.Get[*Impostor](ctx, sl)
+
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(impostor.hero).To(BeIdenticalTo(hero))
+
+		_, err = tinysl.Get[*Impostor](ctx, parent)
+
+		Expect(err).Should(HaveOccurred())
+		Expect(err).Should(BeAssignableToTypeOf(new(tinysl.ConstructorNotFoundError)))
+
+		Expect(sl.Bindings()).To(HaveLen(3))
+		Expect(sl.Bindings()[0].String()).To(Equal("*tinysl_test.Hero (Singleton, inherited)"))
+		Expect(sl.Bindings()[1].String()).To(Equal("*tinysl_test.Impostor (Transient)"))
+	})
+
+	It("should use services registered by child instead of parent ones", func() {
+		parent, err := tinysl.
+			New(tinysl.SilenceUseSingletonWarnings).
+			Add(tinysl.Singleton, nameServiceConstructor).
+			Add(tinysl.Singleton, heroConstructor).
+			ServiceLocator()
+
+		Expect(err).ShouldNot(HaveOccurred())
+
+		sl, err := parent.
+			NewChild().
+			Add(tinysl.Singleton, func() NameService { return NameProvider("Sam") }).
+			Add(tinysl.Transient, impostorConstructor).
+			ServiceLocator()
+
+		Expect(err).ShouldNot(HaveOccurred())
+
+		impostor, err := tinysl.Get[*Impostor](ctx, sl)
+
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(impostor.Name()).To(Equal("Sam"))
+		Expect(impostor.hero.Announce()).To(Equal("Bob is our hero!"))
+
+		nameService, err := tinysl.Get[NameService](ctx, parent)
+
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(nameService.Name()).To(Equal("Bob"))
+		Expect(sl.Bindings()[2].Inherited).To(BeFalse())
+	})
+
+	It("should clean up Singletons of child when child is closed", func() {
+		parentCleanup := make(chan struct{}, 1)
+		childCleanup := make(chan struct{}, 1)
+
+		parentCtx, parentCancel := context.WithCancel(ctx)
+		defer parentCancel()
+
+		parent, err := tinysl.
+			New(tinysl.WithSingletonCleanupContext(parentCtx)).
+			Add(tinysl.Singleton, nameServiceConstructorWithCleanup(func() { parentCleanup <- struct{}{} })).
+			ServiceLocator()
+
+		Expect(err).ShouldNot(HaveOccurred())
+
+		childCtx, childCancel := context.WithCancel(ctx)
+
+		sl, err := parent.
+			NewChild(tinysl.WithSingletonCleanupContext(childCtx)).
+			Add(tinysl.Singleton, heroConstructorWithCleanup(func() { childCleanup <- struct{}{} })).
+			ServiceLocator()
+
+		Expect(err).ShouldNot(HaveOccurred())
+
+		_, err = tinysl.Get[*Hero](ctx, sl)
+
+		Expect(err).ShouldNot(HaveOccurred())
+
+		time.Sleep(time.Millisecond)
+		childCancel()
+
+		Eventually(childCleanup).Should(Receive())
+		Consistently(parentCleanup, time.Millisecond*100).ShouldNot(Receive())
+
+		nameService, err := tinysl.Get[NameService](ctx, parent)
+
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(nameService.Name()).To(Equal("bob"))
+
+		parentCancel()
+
+		Eventually(parentCleanup).Should(Receive())
+	})
+
+	It("should clean up Singletons of child together with parent by default", func() {
+		parentCleanup := make(chan struct{}, 1)
+		childCleanup := make(chan struct{}, 1)
+
+		parentCtx, parentCancel := context.WithCancel(ctx)
+		defer parentCancel()
+
+		parent, err := tinysl.
+			New(tinysl.WithSingletonCleanupContext(parentCtx)).
+			Add(tinysl.Singleton, nameServiceConstructorWithCleanup(func() { parentCleanup <- struct{}{} })).
+			ServiceLocator()
+
+		Expect(err).ShouldNot(HaveOccurred())
+
+		sl, err := parent.
+			NewChild().
+			Add(tinysl.Singleton, heroConstructorWithCleanup(func() { childCleanup <- struct{}{} })).
+			ServiceLocator()
+
+		Expect(err).ShouldNot(HaveOccurred())
+
+		_, err = tinysl.Get[*Hero](ctx, sl)
+
+		Expect(err).ShouldNot(HaveOccurred())
+
+		time.Sleep(time.Millisecond)
+		parentCancel()
+
+		Eventually(childCleanup).Should(Receive())
+		Eventually(parentCleanup).Should(Receive())
+	})
+
+	It("should fall back to registrations of parent Container in child Container", func() {
+		parentCleanup := make(chan struct{}, 1)
+		childCleanup := make(chan struct{}, 1)
+
+		parentCtx, parentCancel := context.WithCancel(ctx)
+		defer parentCancel()
+
+		parent := tinysl.
+			New(tinysl.WithSingletonCleanupContext(parentCtx)).
+			Add(tinysl.Singleton, nameServiceConstructorWithCleanup(func() { parentCleanup <- struct{}{} })).
+			Add(tinysl.Singleton, heroConstructorWithCleanup(func() {}))
+
+		sl, err := parent.
+			Child().
+			Add(tinysl.Singleton, func() (NameService, func(), error) {
+				return NameProvider("Sam"), func() { childCleanup <- struct{}{} }, nil
+			}).
+			ServiceLocator()
+
+		Expect(err).ShouldNot(HaveOccurred())
+
+		hero, err := tinysl.Get[*Hero](ctx, sl)
+
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(hero.Announce()).To(Equal("Sam is our hero!"))
+
+		parentSl, err := parent.ServiceLocator()
+
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(tinysl.MustGet[*Hero](ctx, parentSl).Announce()).To(Equal("bob is our hero!"))
+
+		time.Sleep(time.Millisecond)
+		parentCancel()
+
+		Eventually(childCleanup).Should(Receive())
+		Eventually(parentCleanup).Should(Receive())
+	})
+
+	It("should build new instance with Factory and clean it up with PerContext services", func() {
+		chFirst := make(chan time.Time)
+		chLast := make(chan time.Time)
+
+		var factory tinysl.Factory[HeroArgs, *Hero]
+
+		sl, err := tinysl.
+			New(tinysl.SilenceUseSingletonWarnings).
+			Add(tinysl.PerContext, nameServiceConstructorWithCleanup(func() { chLast <- time.Now() })).
+			AddFactory(heroFactoryWithCleanup(func() { chFirst <- time.Now() })).
+			Add(tinysl.Singleton, func(f tinysl.Factory[HeroArgs, *Hero]) *Impostor {
+				factory = f
+				return &Impostor{}
+			}).
+			ServiceLocator()
+
+		Expect(err).ShouldNot(HaveOccurred())
+
+		_, err = tinysl.Get[*Impostor](ctx, sl)
+
+		Expect(err).ShouldNot(HaveOccurred())
+
+		ctx, cancel := context.WithCancel(ctx)
+		hero, err := factory(ctx, HeroArgs{Title: "Sir"})
+
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(hero.Announce()).To(Equal("Sir bob is our hero!"))
+
+		other, err := factory(ctx, HeroArgs{Title: "Lady"})
+
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(other).NotTo(BeIdenticalTo(hero))
+		Expect(other.Announce()).To(Equal("Lady bob is our hero!"))
+
+		time.Sleep(time.Millisecond)
+		cancel()
+
+		var first, second, last time.Time
+
+		Eventually(chFirst).Should(Receive(&first))
+		Eventually(chFirst).Should(Receive(&second))
+		Eventually(chLast).Should(Receive(&last))
+		Expect(second.Before(last)).To(BeTrue())
+
+		_, err = factory(ctx, HeroArgs{Title: "Sir"})
+
+		Expect(err).Should(HaveOccurred())
+		Expect(errors.Unwrap(err)).Should(MatchError(context.Canceled))
+	})
+
+	It("should resolve result object fields with single cleanup", func() {
+		chFirst := make(chan time.Time)
+		chLast := make(chan time.Time)
+
+		sl, err := tinysl.
+			New(tinysl.SilenceUseSingletonWarnings).
+			Add(tinysl.PerContext, heroesResultConstructor(func() { chLast <- time.Now() })).
+			Add(tinysl.PerContext, func(nameService NameService) (*Impostor, func(), error) {
+				return &Impostor{name: nameService.Name()}, func() { chFirst <- time.Now() }, nil
+			}).
+			ServiceLocator()
+
+		Expect(err).ShouldNot(HaveOccurred())
+
+		ctx, cancel := context.WithCancel(ctx)
+
+		hero, err := tinysl.GetNamed[*Hero](ctx, sl, "hero")
+
+		Expect(err).ShouldNot(HaveOccurred())
+
+		members, err := tinysl.GetAll[Announcer](ctx, sl)
+
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(members).To(HaveLen(1))
+		Expect(members[0]).To(BeIdenticalTo(hero))
+
+		impostor, err := tinysl.Get[*Impostor](ctx, sl)
+
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(impostor.Announce()).To(Equal("Bob is our hero!"))
+
+		time.Sleep(time.Millisecond)
+		cancel()
+
+		var first, last time.Time
+
+		Eventually(chFirst).Should(Receive(&first))
+		Eventually(chLast).Should(Receive(&last))
+		Expect(first.Before(last)).To(BeTrue())
+		Consistently(chLast).ShouldNot(Receive())
+	})
+
 	It("should keep cleanup order for Singleton", func() {
 		appCtx := context.Background()
 		appCtx, cancel := context.WithCancel(appCtx)
@@ -0,0 +1,335 @@
+package tinysl
+
+import (
+	"maps"
+	"reflect"
+	"slices"
+)
+
+// Module groups registrations of a sub-system.
+// Services of module can be used as dependencies only inside of it, unless module exports them.
+// Exported services can be used by modules importing it, by services registered outside of modules
+// and can be requested from ServiceLocator.
+type Module struct {
+	name     string
+	register func(Container)
+	ModuleConfiguration
+}
+
+type ModuleConfiguration struct {
+	Exports []reflect.Type
+	Imports []string
+}
+
+type ModuleOption func(*ModuleConfiguration)
+
+// Returns new Module with name, register adds its services to container passed to it.
+func NewModule(name string, register func(Container), opts ...ModuleOption) Module {
+	var conf ModuleConfiguration
+	for _, opt := range opts {
+		opt(&conf)
+	}
+
+	return Module{name: name, register: register, ModuleConfiguration: conf}
+}
+
+func (m Module) Name() string {
+	return m.name
+}
+
+// Exports services of type T registered by module, including named ones and group members.
+func Export[T any]() ModuleOption {
+	return func(opt *ModuleConfiguration) {
+		opt.Exports = append(opt.Exports, reflect.TypeOf(new(T)).Elem())
+	}
+}
+
+// Allows module to use services exported by modules.
+func Import(modules ...Module) ModuleOption {
+	return func(opt *ModuleConfiguration) {
+		for _, m := range modules {
+			opt.Imports = append(opt.Imports, m.name)
+		}
+	}
+}
+
+// Creates new Container, adds module and returns newly-created container.
+func AddModule(module Module) Container {
+	return New().AddModule(module)
+}
+
+func (c *container) AddModule(module Module) Container {
+	if errVal := c.err.Load(); errVal != nil {
+		return c
+	}
+
+	if module.name == "" {
+		c.err.Store(newModuleError(ErrModuleWithoutName, module.name))
+		return c
+	}
+
+	mc := newContainer(ContainerConfiguration{
+		Ctx:                         c.ctx,
+		SilenceUseSingletonWarnings: c.ignoreScopeAnalyzerErrors,
+		ResolveByAssignability:      c.resolveByAssignability,
+	})
+
+	if module.register != nil {
+		module.register(mc)
+	}
+
+	if errVal := mc.err.Load(); errVal != nil {
+		c.err.Store(newModuleError(errVal.(error), module.name))
+		return c
+	}
+
+	for _, export := range module.Exports {
+		if !mc.registers(export) {
+			c.err.Store(newModuleError(newExportNotRegisteredError(export), module.name))
+			return c
+		}
+	}
+
+	for key, records := range mc.constructors {
+		if key.role == decorator && !mc.registersService(key.serviceKey) {
+			c.err.Store(newModuleError(
+				newBadConstructorError(ErrDecoratorOutsideModule, reflect.TypeOf(records[0].constructor)),
+				module.name,
+			))
+			return c
+		}
+	}
+
+	registered := maps.Clone(mc.constructors)
+	if mc.defaults != nil {
+		for key, records := range mc.defaults.constructors {
+			registered[key] = append(slices.Clone(registered[key]), records...)
+		}
+	}
+
+	c.constructorsRWM.Lock()
+	defer c.constructorsRWM.Unlock()
+
+	if _, ok := c.modules[module.name]; ok {
+		c.err.Store(newModuleError(ErrDuplicateModule, module.name))
+		return c
+	}
+
+	// default registrations of module are merged with default registrations of container
+	if mc.defaults != nil {
+		defaults := c.defaultRegistrations()
+		defaults.constructorsRWM.Lock()
+		defer defaults.constructorsRWM.Unlock()
+
+		if err := defaults.addModuleRecords(module.keyPrivate(mc.defaults.constructors, registered)); err != nil {
+			c.err.Store(newModuleError(err, module.name))
+			return c
+		}
+	}
+
+	if err := c.addModuleRecords(module.keyPrivate(mc.constructors, registered)); err != nil {
+		c.err.Store(newModuleError(err, module.name))
+		return c
+	}
+
+	if c.modules == nil {
+		c.modules = make(map[string]ModuleConfiguration)
+	}
+
+	c.modules[module.name] = module.ModuleConfiguration
+
+	return c
+}
+
+// Reports if container has service, group member, map entry or factory of type t.
+func (c *container) registers(t reflect.Type) bool {
+	for key := range c.constructors {
+		if key.serviceType == t && key.role != decorator {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Reports if container has service of type and name the decorator can decorate.
+func (c *container) registersService(key serviceKey) bool {
+	if _, ok := c.constructors[containerKey{key, service}]; ok {
+		return true
+	}
+
+	if c.defaults != nil {
+		_, ok := c.defaults.constructors[containerKey{key, service}]
+		return ok
+	}
+
+	return false
+}
+
+// Returns constructors of module with services it does not export keyed by module,
+// so that they do not collide with services of container and other modules.
+// Dependencies of module on its own services that are not exported are linked to them the same way.
+// registered contains all regular and default registrations of module.
+func (m Module) keyPrivate(
+	constructors map[containerKey][]*containerRecord,
+	registered map[containerKey][]*containerRecord,
+) map[containerKey][]*containerRecord {
+	result := make(map[containerKey][]*containerRecord, len(constructors))
+	for key, records := range constructors {
+		for _, r := range records {
+			if r.module == m.name {
+				// shared by several keys (aliases)
+				continue
+			}
+
+			r.module = m.name
+			r.private = !slices.Contains(m.Exports, r.serviceType)
+
+			for i, dep := range r.dependencies {
+				r.dependencies[i] = m.linkDependency(r, dep, registered)
+			}
+		}
+
+		if !slices.Contains(m.Exports, key.serviceType) {
+			key.module = m.name
+		}
+
+		result[key] = records
+	}
+
+	return result
+}
+
+// Returns key of service registered by module that is not exported by it, if dependency of r is resolved with it.
+func (m Module) linkDependency(r *containerRecord, dep serviceKey, registered map[containerKey][]*containerRecord) serviceKey {
+	targets := []containerKey{{dep, service}}
+	if slices.ContainsFunc(registered[containerKey{dep, contextual}], func(c *containerRecord) bool {
+		return slices.Contains(c.consumers, r.serviceType)
+	}) {
+		targets = append(targets, containerKey{dep, contextual})
+	}
+
+	switch t := dep.serviceType; {
+	case t.Kind() == reflect.Slice:
+		targets = append(targets, containerKey{serviceKey{serviceType: t.Elem(), name: dep.name}, group})
+	case t.Kind() == reflect.Map && t.Key() == stringType:
+		targets = append(targets, containerKey{serviceKey{serviceType: t.Elem(), name: dep.name}, mapEntry})
+	case t.Implements(factoryInterface):
+		f := reflect.Zero(t).Interface().(factoryDependency)
+		targets = append(targets, containerKey{serviceKey{serviceType: f.serviceType(), name: dep.name}, factory})
+	}
+
+	for _, target := range targets {
+		if _, ok := registered[target]; ok && !slices.Contains(m.Exports, target.serviceType) {
+			dep.module = m.name
+			break
+		}
+	}
+
+	return dep
+}
+
+// Services and factories of module must not be registered by container or other modules.
+// Services module does not export are keyed by it, so they can collide only with services of the same module.
+func (c *container) addModuleRecords(constructors map[containerKey][]*containerRecord) error {
+	for key, records := range constructors {
+		for _, r := range records {
+			for _, registered := range c.constructors[key] {
+				if key.role == service || key.role == factory && registered.args == r.args {
+					return newServiceBuilderError(ErrDuplicateConstructor, r.lifetime, key.String())
+				}
+			}
+		}
+	}
+
+	for key, records := range constructors {
+		c.constructors[key] = append(c.constructors[key], records...)
+	}
+
+	return nil
+}
+
+// Reports error if service registered by module can not be used by dependant module as exportedAs type.
+// Services registered outside of modules can be used everywhere and can use services exported by any module.
+func (c *container) checkVisibility(dependant, module string, exportedAs reflect.Type, depName string) error {
+	if module == "" || module == dependant {
+		return nil
+	}
+
+	if !slices.Contains(c.modules[module].Exports, exportedAs) {
+		return newModuleVisibilityError(depName, module, false)
+	}
+
+	if dependant != "" && !slices.Contains(c.modules[dependant].Imports, module) {
+		return newModuleVisibilityError(depName, module, true)
+	}
+
+	return nil
+}
+
+// Imported modules must be added to container.
+func (c *container) checkImports() error {
+	for name, m := range c.modules {
+		for _, imported := range m.Imports {
+			if _, ok := c.modules[imported]; !ok {
+				return newModuleError(newModuleNotAddedError(imported), name)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Removes services of modules that are not exported from services that can be requested from ServiceLocator.
+// Dependencies of locator records are already linked, so removed services are still used as dependencies.
+func (c *container) hideModuleServices(
+	constructorsByType map[serviceKey]*locatorRecord,
+	inferred map[serviceKey]*containerRecord,
+) {
+	for key, rec := range constructorsByType {
+		var err error
+
+		switch members, isCollection := c.collectionMembers(key); {
+		case key.module != "":
+			err = newModuleVisibilityError(key.String(), key.module, false)
+		case inferred[key] != nil, rec.args != nil:
+			err = c.checkVisibility("", rec.module, rec.serviceType, key.String())
+		case isCollection && rec.module == "":
+			for _, member := range members {
+				if err = c.checkVisibility("", member.module, member.serviceType, key.String()); err != nil {
+					break
+				}
+			}
+		default:
+			err = c.checkVisibility("", rec.module, key.serviceType, key.String())
+		}
+
+		if err != nil {
+			delete(constructorsByType, key)
+		}
+	}
+}
+
+// Returns module that registers dependency of type and name of key, but does not export it.
+func (c *container) privateModule(key serviceKey) (string, bool) {
+	types := []reflect.Type{key.serviceType}
+	switch t := key.serviceType; {
+	case t.Kind() == reflect.Slice, t.Kind() == reflect.Map && t.Key() == stringType:
+		types = append(types, t.Elem())
+	case t.Implements(factoryInterface):
+		types = append(types, reflect.Zero(t).Interface().(factoryDependency).serviceType())
+	}
+
+	var modules []string
+	for k := range c.constructors {
+		if k.module != "" && k.role != decorator && k.name == key.name && slices.Contains(types, k.serviceType) {
+			modules = append(modules, k.module)
+		}
+	}
+
+	if len(modules) == 0 {
+		return "", false
+	}
+
+	return slices.Min(modules), true
+}
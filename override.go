@@ -0,0 +1,93 @@
+package tinysl
+
+import (
+	"context"
+	"maps"
+	"reflect"
+)
+
+type overridesKey struct{}
+
+// Resolves service instead of its registration in context overrides are attached to.
+type override func(ctx context.Context) (any, error)
+
+// Returns context in which service of type T is resolved as value instead of its registration,
+// for this context and all contexts derived from it.
+// T can be interface service is bound to with As or by ResolveByAssignability,
+// then service is resolved as value only where it is requested as T.
+// Only PerContext and Transient services can be overridden, since Singletons are shared between contexts.
+// Singletons are built without overrides, even if they depend on overridden services.
+// Overrides of services that are not registered are ignored.
+func Override[T any](ctx context.Context, value T) context.Context {
+	return OverrideNamed(ctx, "", value)
+}
+
+// Returns context in which service of type T registered with name is resolved as value instead of its registration.
+func OverrideNamed[T any](ctx context.Context, name string, value T) context.Context {
+	return OverrideNamedFunc(ctx, name, func(context.Context) (T, error) { return value, nil })
+}
+
+// Returns context in which service of type T is resolved with constructor instead of its registration,
+// for this context and all contexts derived from it.
+// Constructor is called with context.Context service is resolved with,
+// and its result is shared within that context same as PerContext service, if overridden service is PerContext.
+func OverrideFunc[T any](ctx context.Context, constructor func(context.Context) (T, error)) context.Context {
+	return OverrideNamedFunc(ctx, "", constructor)
+}
+
+// Returns context in which service of type T registered with name is resolved with constructor instead of its registration.
+func OverrideNamedFunc[T any](ctx context.Context, name string, constructor func(context.Context) (T, error)) context.Context {
+	key := serviceKey{serviceType: reflect.TypeOf(new(T)).Elem(), name: name}
+
+	// overrides of parent context are copied, so they are not changed by derived contexts
+	overrides := maps.Clone(overridesOf(ctx))
+	if overrides == nil {
+		overrides = make(map[serviceKey]override)
+	}
+
+	overrides[key] = func(ctx context.Context) (any, error) { return constructor(ctx) }
+
+	return context.WithValue(ctx, overridesKey{}, overrides)
+}
+
+func overridesOf(ctx context.Context) map[serviceKey]override {
+	if ctx == nil {
+		return nil
+	}
+
+	overrides, _ := ctx.Value(overridesKey{}).(map[serviceKey]override)
+
+	return overrides
+}
+
+// Returns context Singleton is built with, so overrides do not leak into instance shared between contexts.
+func withoutOverrides(ctx context.Context) context.Context {
+	if len(overridesOf(ctx)) == 0 {
+		return ctx
+	}
+
+	return context.WithValue(ctx, overridesKey{}, map[serviceKey]override(nil))
+}
+
+// Returns override of record requested with key attached to ctx and key it is attached with.
+// Override of key record is requested with, such as alias or inferred binding, is used before override of record itself.
+func overrideOf(ctx context.Context, key serviceKey, record *locatorRecord) (override, serviceKey, bool) {
+	overrides := overridesOf(ctx)
+	if o, ok := overrides[key]; ok {
+		return o, key, true
+	}
+
+	o, ok := overrides[record.key()]
+
+	return o, record.key(), ok
+}
+
+// Builds service with override same as with constructor returning (T, error).
+func (o override) build(ctx context.Context, record *locatorRecord) (any, Cleanup, error) {
+	service, err := o(ctx)
+	if err != nil {
+		return nil, nil, newRecordBuilderError(newConstructorError(err), record.record)
+	}
+
+	return service, func() {}, nil
+}
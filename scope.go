@@ -2,13 +2,145 @@ package tinysl
 
 import (
 	"context"
+	"errors"
 	"reflect"
 	"runtime"
+	"slices"
 	"sync"
 )
 
 var mod = uint64(866285) // just a random uint with good enough spread
 
+type explicitScopeKey struct{}
+
+// PerContext scope shared by context it is created with and all contexts derived from it.
+// Scopes of contexts that are not created with NewScope are tracked by it as well, so their cleanup can be awaited.
+type explicitScope struct {
+	// done when scope is ended
+	ctx context.Context
+	// context returned by NewScope, services resolved from the scope by nested scopes are built with it
+	scoped context.Context
+	// scope of context NewScope was called with
+	parent *explicitScope
+	// scopes of each ServiceLocator the scope was used with
+	entries []*explicitScopeEntry
+	mu      sync.Mutex
+}
+
+func (s *explicitScope) track(entry *explicitScopeEntry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.entries = append(s.entries, entry)
+}
+
+// Waits until scope is ended and cleaned up by each ServiceLocator it was used with.
+func (s *explicitScope) await() error {
+	<-s.ctx.Done()
+
+	return s.awaitEntries()
+}
+
+func (s *explicitScope) awaitEntries() error {
+	s.mu.Lock()
+	entries := slices.Clone(s.entries)
+	s.mu.Unlock()
+
+	errs := make([]error, 0, len(entries))
+	for _, entry := range entries {
+		<-entry.released
+		errs = append(errs, entry.err)
+	}
+
+	return errors.Join(errs...)
+}
+
+type ScopeConfiguration struct {
+	SyncCleanup bool
+}
+
+type ScopeOption func(*ScopeConfiguration)
+
+// Makes function ending scope wait until the scope is cleaned up,
+// including cleanups deferred by leases of Go and Detach.
+var SyncCleanup ScopeOption = func(opt *ScopeConfiguration) { opt.SyncCleanup = true }
+
+// Returns context with new PerContext scope, shared by all contexts derived from it,
+// regardless of context.WithValue, context.WithTimeout and other wrappers.
+// Scope is ended and its PerContext services are cleaned up when end is called or ctx is done.
+// Explicit scopes are looked up without context pointer, so they do not depend on SetGoHasMovingGC.
+// PerContext services resolved with derived contexts that have other overrides or scoped values are kept apart.
+//
+// Scope created with context of another explicit scope is nested in it:
+// PerContext services are resolved from the outermost scope with its context,
+// unless they are registered with PerInnermostScope.
+// Nested scope is cleaned up before its parent, so the whole tree is torn down when the outer scope is ended.
+func NewScope(ctx context.Context, opts ...ScopeOption) (scoped context.Context, end func()) {
+	var conf ScopeConfiguration
+	for _, opt := range opts {
+		opt(&conf)
+	}
+
+	parent, _ := ctx.Value(explicitScopeKey{}).(*explicitScope)
+	ctx, cancel := context.WithCancel(ctx)
+	s := &explicitScope{ctx: ctx, parent: parent}
+	s.scoped = context.WithValue(ctx, explicitScopeKey{}, s)
+
+	if !conf.SyncCleanup {
+		return s.scoped, cancel
+	}
+
+	return s.scoped, func() {
+		cancel()
+		_ = s.await()
+	}
+}
+
+// Scopes of contexts keyed by their pointers, that are not cleaned up yet.
+var ctxScopes sync.Map
+
+// Waits until scope of ctx is ended and its PerContext services are cleaned up,
+// returns errors recovered from panics of cleanups of scope created with NewScope.
+// For context that is not created with NewScope it waits for scope of ctx itself, which is ended when ctx is done,
+// but not for scopes of contexts derived from it, and returns errors recovered from panics of its cleanups.
+// Its scope is not tracked after it is cleaned up, so if it is already cleaned up when AwaitCleanup is called,
+// panics recovered from its cleanups are only logged.
+func AwaitCleanup(ctx context.Context) error {
+	if s, ok := ctx.Value(explicitScopeKey{}).(*explicitScope); ok {
+		return s.await()
+	}
+
+	ctx = scopeContext(ctx)
+	if ctx.Done() == nil {
+		return ErrAwaitWithoutScope
+	}
+
+	<-ctx.Done()
+
+	// scope that is already cleaned up is not tracked
+	if s, ok := ctxScopes.Load(uint64(reflect.ValueOf(ctx).Pointer())); ok {
+		return s.(*explicitScope).awaitEntries()
+	}
+
+	return nil
+}
+
+// Removes scope of ctx from tracked scopes, once it is cleaned up by each ServiceLocator it was used with.
+func untrackCtxScope(key uint64, s *explicitScope) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, entry := range s.entries {
+		select {
+		case <-entry.released:
+		default:
+			return
+		}
+	}
+
+	ctxScopes.CompareAndDelete(key, s)
+}
+
 var ctxScopeKeyPool = sync.Pool{
 	New: func() any {
 		return &ctxScopeKey{}
@@ -71,6 +203,134 @@ func (cs *serviceScope) unlock() {
 type contextScope struct {
 	cleanup  *cleanupNode
 	services []*serviceScope
+	// cleanups of services built by factories with context.Context of the scope
+	factoryCleanups []Cleanup
+	// cleanups of nested scopes returning errors recovered from panics
+	children map[*explicitScope]func() error
+	// errors recovered from panics of cleanups of nested scopes cleaned up before the scope
+	childrenErrs []error
+	// scope the scope is nested in
+	outer *contextScope
+	// context services of nested scope are built with
+	ctx context.Context
+	// overrides and scoped values of context the scope is created for
+	variant scopeVariant
+	// scopes of contexts sharing the scope with other overrides or scoped values
+	variants []*contextScope
+	// services resolved with overrides of aliases and inferred bindings by their keys
+	overrides map[serviceKey]*serviceScope
+	mu        sync.Mutex
+}
+
+// Returns scope of service resolved with override of alias or inferred binding key,
+// which is kept apart from service the key is bound to.
+func (cs *contextScope) overridden(key serviceKey) *serviceScope {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	if cs.overrides == nil {
+		cs.overrides = make(map[serviceKey]*serviceScope)
+	}
+
+	instance, ok := cs.overrides[key]
+	if !ok {
+		instance = &serviceScope{}
+		cs.overrides[key] = instance
+	}
+
+	return instance
+}
+
+// Overrides and scoped values PerContext services are built with.
+// Contexts derived from context of the scope share it, but can have their own overrides and scoped values,
+// so services built with them are kept in separate scope.
+type scopeVariant struct {
+	overrides map[serviceKey]override
+	values    map[reflect.Type]any
+}
+
+func variantOf(ctx context.Context) scopeVariant {
+	return scopeVariant{overrides: overridesOf(ctx), values: scopedValuesOf(ctx)}
+}
+
+// Overrides and scoped values are copied on each change, so variants are compared by their maps.
+func (v scopeVariant) is(other scopeVariant) bool {
+	return reflect.ValueOf(v.overrides).UnsafePointer() == reflect.ValueOf(other.overrides).UnsafePointer() &&
+		reflect.ValueOf(v.values).UnsafePointer() == reflect.ValueOf(other.values).UnsafePointer()
+}
+
+// Returns context service resolved from the scope by scope nested in it is built with,
+// so it is cleaned up with the scope, but still uses overrides and scoped values of ctx.
+func (cs *contextScope) contextOf(ctx context.Context) context.Context {
+	v := variantOf(ctx)
+	if cs.variant.is(v) {
+		return cs.ctx
+	}
+
+	scoped := context.WithValue(cs.ctx, overridesKey{}, v.overrides)
+
+	return context.WithValue(scoped, scopedValuesKey{}, v.values)
+}
+
+func (cs *contextScope) addChild(s *explicitScope, fn func() error) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	if cs.children == nil {
+		cs.children = make(map[*explicitScope]func() error)
+	}
+
+	cs.children[s] = fn
+}
+
+// Nested scope that is cleaned up before its parent reports its errors with errors of parent.
+func (cs *contextScope) removeChild(s *explicitScope, err error) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	if _, ok := cs.children[s]; ok && err != nil {
+		cs.childrenErrs = append(cs.childrenErrs, err)
+	}
+
+	delete(cs.children, s)
+}
+
+func (cs *contextScope) addCleanup(fn Cleanup) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	cs.factoryCleanups = append(cs.factoryCleanups, fn)
+}
+
+// Nested scopes can depend on services of the scope, so they are cleaned first.
+// Services built by factories depend on PerContext services,
+// so they are cleaned next in reverse order of creation.
+// Returns errors recovered from panics of cleanups.
+func (cs *contextScope) clean() error {
+	cs.mu.Lock()
+	factoryCleanups := cs.factoryCleanups
+	children := cs.children
+	errs := cs.childrenErrs
+	cs.factoryCleanups = nil
+	cs.children = nil
+	cs.childrenErrs = nil
+	cs.overrides = nil
+	cs.outer = nil
+	cs.mu.Unlock()
+
+	for _, child := range children {
+		errs = append(errs, child())
+	}
+
+	for i := len(factoryCleanups) - 1; i >= 0; i-- {
+		errs = append(errs, factoryCleanups[i].callWithRecovery(PerContext))
+	}
+
+	if !cs.cleanup.empty() {
+		errs = append(errs, Cleanup(cs.cleanup.clean).callWithRecovery(PerContext))
+	}
+
+	return errors.Join(errs...)
 }
 
 func newContextInstances(size int32, buildCleanupNode func() *cleanupNode) *contextInstances {
@@ -92,9 +352,17 @@ func newContextInstances(size int32, buildCleanupNode func() *cleanupNode) *cont
 type contextInstances struct {
 	serviceScopesPool sync.Pool
 	partitions        [18]sync.Map
+	explicit          sync.Map
 }
 
 func (ci *contextInstances) get(ctx context.Context) *contextScope {
+	if s, ok := ctx.Value(explicitScopeKey{}).(*explicitScope); ok {
+		return ci.getExplicit(s)
+	}
+
+	// detached context shares scope of context it is detached from
+	ctx = scopeContext(ctx)
+
 	ctxKey := getCtxScopeKey(ctx)
 	ctxKV := ctxKey.key()
 
@@ -162,32 +430,162 @@ func (ci *contextInstances) get(ctx context.Context) *contextScope {
 		}
 	}
 
-	scopeVal, ok := ci.partitions[partIndex].LoadOrStore(ctxKV, ci.serviceScopesPool.Get())
+	newScope := ci.serviceScopesPool.Get().(*contextScope)
+	newScope.variant = variantOf(ctx)
+
+	scopeVal, ok := ci.partitions[partIndex].LoadOrStore(ctxKV, newScope)
 	scope := scopeVal.(*contextScope)
 
-	if !ok {
-		ctxKey.pin()
-		context.AfterFunc(ctx, func() {
-			if scopeVal, ok := ci.partitions[partIndex].LoadAndDelete(ctxKV); ok {
-				scope := scopeVal.(*contextScope)
+	if ok {
+		newScope.variant = scopeVariant{}
+		ci.serviceScopesPool.Put(newScope)
+		cleanCtxKey(ctxKey)
 
-				if !scope.cleanup.empty() {
-					Cleanup(scope.cleanup.clean).CallWithRecovery(PerContext)
-				}
+		return scope
+	}
 
-				for key := range scope.services {
-					scope.services[key].lock()
-					scope.services[key].value = nil
-					scope.services[key].unlock()
-				}
+	entry := &explicitScopeEntry{scope: scope, released: make(chan struct{})}
+	// tracked scope does not keep ctx, so ctx is not pinned by it
+	trackedVal, _ := ctxScopes.LoadOrStore(ctxKV, &explicitScope{})
+	tracked := trackedVal.(*explicitScope)
+	tracked.track(entry)
 
-				ci.serviceScopesPool.Put(scope)
+	ctxKey.pin()
+	context.AfterFunc(ctx, func() {
+		afterLeases(ctx, func() {
+			if scopeVal, ok := ci.partitions[partIndex].LoadAndDelete(ctxKV); ok {
+				entry.err = ci.release(scopeVal.(*contextScope))
 				cleanCtxKey(ctxKey)
+				close(entry.released)
+				untrackCtxScope(ctxKV, tracked)
 			}
 		})
-	} else {
-		cleanCtxKey(ctxKey)
-	}
+	})
 
 	return scope
 }
+
+type explicitScopeEntry struct {
+	scope *contextScope
+	// closed when scope is cleaned up
+	released chan struct{}
+	// errors recovered from panics of cleanups
+	err error
+}
+
+// Explicit scope is keyed by its handle, so it does not need pinning.
+func (ci *contextInstances) getExplicit(s *explicitScope) *contextScope {
+	if entryVal, ok := ci.explicit.Load(s); ok {
+		return entryVal.(*explicitScopeEntry).scope
+	}
+
+	entry := &explicitScopeEntry{
+		scope:    ci.serviceScopesPool.Get().(*contextScope),
+		released: make(chan struct{}),
+	}
+
+	entry.scope.ctx = s.scoped
+	entry.scope.variant = variantOf(s.scoped)
+
+	var parent *contextScope
+	if s.parent != nil {
+		parent = ci.getExplicit(s.parent)
+		entry.scope.outer = parent
+	}
+
+	if entryVal, ok := ci.explicit.LoadOrStore(s, entry); ok {
+		entry.scope.outer = nil
+		entry.scope.ctx = nil
+		entry.scope.variant = scopeVariant{}
+		ci.serviceScopesPool.Put(entry.scope)
+
+		return entryVal.(*explicitScopeEntry).scope
+	}
+
+	// both nested scope and its parent are done when parent is ended,
+	// so whichever is first cleans nested scope and parent waits for it
+	release := func() error {
+		if _, ok := ci.explicit.LoadAndDelete(s); ok {
+			entry.err = ci.release(entry.scope)
+			close(entry.released)
+
+			return entry.err
+		}
+
+		<-entry.released
+
+		return entry.err
+	}
+
+	s.track(entry)
+
+	if parent != nil {
+		parent.addChild(s, release)
+	}
+
+	context.AfterFunc(s.ctx, func() {
+		// leases of nested scope lease its parents, so parent is not cleaned up before it
+		afterLeases(s, func() {
+			err := release()
+
+			if parent != nil {
+				parent.removeChild(s, err)
+			}
+		})
+	})
+
+	return entry.scope
+}
+
+// Returns scope PerContext services are resolved from with overrides and scoped values of ctx.
+func (ci *contextInstances) variant(scope *contextScope, ctx context.Context) *contextScope {
+	v := variantOf(ctx)
+	if scope.variant.is(v) {
+		return scope
+	}
+
+	scope.mu.Lock()
+	defer scope.mu.Unlock()
+
+	for _, variant := range scope.variants {
+		if variant.variant.is(v) {
+			return variant
+		}
+	}
+
+	variant := ci.serviceScopesPool.Get().(*contextScope)
+	variant.variant = v
+	variant.outer = scope.outer
+	variant.ctx = scope.ctx
+	scope.variants = append(scope.variants, variant)
+
+	return variant
+}
+
+// Cleans up scope and returns it to the pool.
+// Variants of scope are cleaned up before it, since they are ended together.
+func (ci *contextInstances) release(scope *contextScope) error {
+	scope.mu.Lock()
+	variants := scope.variants
+	scope.variants = nil
+	scope.mu.Unlock()
+
+	errs := make([]error, 0, len(variants)+1)
+	for _, variant := range variants {
+		errs = append(errs, ci.release(variant))
+	}
+
+	errs = append(errs, scope.clean())
+
+	for key := range scope.services {
+		scope.services[key].lock()
+		scope.services[key].value = nil
+		scope.services[key].unlock()
+	}
+
+	scope.variant = scopeVariant{}
+	scope.ctx = nil
+	ci.serviceScopesPool.Put(scope)
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,55 @@
+package tinysl
+
+import (
+	"context"
+	"maps"
+	"reflect"
+)
+
+type scopedValuesKey struct{}
+
+// Returns context with value of type T seeded into its PerContext scope,
+// for this context and all contexts derived from it.
+// Value is resolved by constructor Scoped[T], that declares T in Container.
+func WithScopedValue[T any](ctx context.Context, value T) context.Context {
+	// values of parent context are copied, so they are not changed by derived contexts
+	values := maps.Clone(scopedValuesOf(ctx))
+	if values == nil {
+		values = make(map[reflect.Type]any)
+	}
+
+	values[reflect.TypeOf(new(T)).Elem()] = value
+
+	return context.WithValue(ctx, scopedValuesKey{}, values)
+}
+
+// Constructor of value of type T supplied at runtime with WithScopedValue, registered as PerContext:
+//
+//	tinysl.Add(tinysl.PerContext, tinysl.Scoped[*http.Request])
+//
+// Constructors can depend on T same as on any other service, and ServiceLocator() reports
+// dependencies on types that are not declared. Resolving T with context that was not seeded
+// returns ScopedValueNotSeededError.
+func Scoped[T any](ctx context.Context) (T, error) {
+	t := reflect.TypeOf(new(T)).Elem()
+	value, ok := scopedValuesOf(ctx)[t]
+	if !ok {
+		var zero T
+		return zero, newScopedValueNotSeededError(t)
+	}
+
+	// nil interface value is seeded as zero value of T
+	service, _ := value.(T)
+
+	return service, nil
+}
+
+func scopedValuesOf(ctx context.Context) map[reflect.Type]any {
+	if ctx == nil {
+		return nil
+	}
+
+	values, _ := ctx.Value(scopedValuesKey{}).(map[reflect.Type]any)
+
+	return values
+}
@@ -2,11 +2,13 @@ package tinysl
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"net/http"
 	"reflect"
 	"runtime/debug"
+	"strings"
 	"sync/atomic"
 )
 
@@ -37,15 +39,23 @@ type Lifetime int
 type Cleanup func()
 
 func (c Cleanup) CallWithRecovery(l Lifetime) {
+	_ = c.callWithRecovery(l)
+}
+
+// Returns error recovered from panic, after it is logged.
+func (c Cleanup) callWithRecovery(l Lifetime) (err error) {
 	defer func() {
 		if rp := recover(); rp != nil {
+			err = newRecoveredError(rp, debug.Stack())
 			logger().Error(
 				fmt.Sprintf("recovered from panic during %s cleanup", l),
-				"error", newRecoveredError(rp, debug.Stack()))
+				"error", err)
 		}
 	}()
 
 	c()
+
+	return nil
 }
 
 type Logger interface {
@@ -62,6 +72,8 @@ var (
 	loggerPtr        atomic.Pointer[Logger]
 )
 
+// Pins contexts PerContext scopes are keyed by, in case Go GC moves values in memory.
+// Scopes created with NewScope are not keyed by context pointer and do not need it.
 func SetGoHasMovingGC() {
 	goHasMovingGC.Store(true)
 }
@@ -86,33 +98,113 @@ type Container interface {
 	// For Singleton constructor should be of type func(T1, T2, ...) (T, error),
 	// for Transient and PerContext constructor should be of type func(context.Context, T1, T2, ...),
 	// where T is exact type of service.
-	Add(lifetime Lifetime, constructor any) Container
+	// Use WithName option to register several constructors of the same type.
+	Add(lifetime Lifetime, constructor any, opts ...RegistrationOption) Container
+	// Adds factory constructor of service built with runtime arguments.
+	// Constructor should be of type func(context.Context, T1, T2, ..., Args) [T|(T, error)|(T, func(), error)],
+	// where context.Context is optional and Args is arguments passed to Factory[Args, T].
+	AddFactory(constructor any, opts ...RegistrationOption) Container
+	// Adds each exported method of provider as a constructor.
+	// Method lifetime is chosen by ProviderLifetimes if provider implements it,
+	// otherwise methods prefixed with Transient or PerContext get that lifetime and all other methods are Singletons.
+	AddProvider(provider any) Container
+	// Adds services of module, only services exported by module can be used outside of it.
+	AddModule(module Module) Container
+	// Adds already constructed service as a Singleton of its type.
+	// Use WithCleanup option to provide its cleanup function.
+	AddValue(value any, opts ...RegistrationOption) Container
 	// Decorate constructor of service.
-	Decorate(lifetime Lifetime, constructor any) Container
+	Decorate(lifetime Lifetime, constructor any, opts ...RegistrationOption) Container
 	// Replaces constructor of service with same lifetime as registered before.
-	Replace(constructor any) Container
+	Replace(constructor any, opts ...RegistrationOption) Container
+	// Returns new Container which falls back to registrations of this Container for services it does not register.
+	// Registrations are read by ServiceLocator() of child, so instances are not shared with ServiceLocator of parent.
+	// Singletons of child are cleaned up when context of WithSingletonCleanupContext option of parent is done.
+	Child(opts ...ContainerOption) Container
 	// Returns ServiceLocator or error.
 	ServiceLocator() (sl ServiceLocator, err error)
 }
 
-// ServiceLocator allows fetching service using its type name.
+// ServiceLocator allows fetching service using its type.
 type ServiceLocator interface {
-	// Returns service instance associated with service type name.
-	Get(ctx context.Context, serviceName string) (any, error)
+	// Returns service instance associated with service type.
+	Get(ctx context.Context, serviceType reflect.Type) (any, error)
+	// Returns service instance associated with service type and name.
+	GetNamed(ctx context.Context, serviceType reflect.Type, name string) (any, error)
 	// Ensures ServiceLocator has service registered.
 	// Will report error through ServiceLocator.Err()
-	EnsureAvailable(serviceName string)
-	// Reports error if ServiceLocator.EnsureAvailable(serviceName) failed to find service.
+	EnsureAvailable(serviceType reflect.Type)
+	// Ensures ServiceLocator has service registered with name.
+	// Will report error through ServiceLocator.Err()
+	EnsureAvailableNamed(serviceType reflect.Type, name string)
+	// Reports error if ServiceLocator.EnsureAvailable(serviceType) failed to find service.
 	Err() error
+	// Returns services available in ServiceLocator sorted by type and name.
+	Bindings() []Binding
+	// Returns new Container which ServiceLocator falls back to this ServiceLocator for services it does not register.
+	// Services of parent are resolved by parent, so its Singletons are shared with child,
+	// while Singletons of child are cleaned up when context of WithSingletonCleanupContext option of child is done,
+	// which defaults to context Singletons of parent are cleaned up with.
+	NewChild(opts ...ContainerOption) Container
+}
+
+// Binding describes service available in ServiceLocator.
+type Binding struct {
+	// Type service is requested with.
+	ServiceType reflect.Type
+	// Type of service built for ServiceType,
+	// differs from ServiceType for interfaces bound with As or inferred with ResolveByAssignability.
+	Implementation reflect.Type
+	Name           string
+	Lifetime       Lifetime
+	// Reports if binding was inferred with ResolveByAssignability.
+	Inferred bool
+	// Reports if binding uses registration with AsDefault, since there is no regular registration.
+	Default bool
+	// Reports if binding uses regular registration instead of registration with AsDefault.
+	OverridesDefault bool
+	// Reports if binding is inherited from parent ServiceLocator.
+	Inherited bool
+	// Type of services binding is used to build, if it is registered with WhenBuilding option.
+	WhenBuilding reflect.Type
+}
+
+func (b Binding) String() string {
+	s := serviceKey{serviceType: b.ServiceType, name: b.Name}.String()
+	if b.Implementation != b.ServiceType {
+		s += " -> " + b.Implementation.String()
+	}
+
+	details := []string{b.Lifetime.String()}
+	if b.Inferred {
+		details = append(details, "inferred")
+	}
+
+	if b.Default {
+		details = append(details, "default")
+	}
+
+	if b.OverridesDefault {
+		details = append(details, "overrides default")
+	}
+
+	if b.Inherited {
+		details = append(details, "inherited")
+	}
+
+	if b.WhenBuilding != nil {
+		details = append(details, "when building "+b.WhenBuilding.String())
+	}
+
+	return fmt.Sprintf("%s (%s)", s, strings.Join(details, ", "))
 }
 
 // Returns service registered in ServiceLocator, or error if such occurred.
 func Get[T any](ctx context.Context, sl ServiceLocator) (T, error) {
 	var nilValue T
-	serviceType := reflect.TypeOf(new(T))
-	serviceName := serviceType.Elem().String()
+	serviceType := reflect.TypeOf(new(T)).Elem()
 
-	s, err := sl.Get(ctx, serviceName)
+	s, err := sl.Get(ctx, serviceType)
 	if err != nil {
 		return nilValue, err
 	}
@@ -130,20 +222,160 @@ func MustGet[T any](ctx context.Context, sl ServiceLocator) T {
 	return s
 }
 
+// Returns all members of group of T registered in ServiceLocator, or error if such occurred.
+func GetAll[T any](ctx context.Context, sl ServiceLocator) ([]T, error) {
+	return Get[[]T](ctx, sl)
+}
+
+// Returns service registered in ServiceLocator with name, or error if such occurred.
+func GetNamed[T any](ctx context.Context, sl ServiceLocator, name string) (T, error) {
+	var nilValue T
+	serviceType := reflect.TypeOf(new(T)).Elem()
+
+	s, err := sl.GetNamed(ctx, serviceType, name)
+	if err != nil {
+		return nilValue, err
+	}
+
+	return s.(T), nil
+}
+
+// Returns service registered in ServiceLocator with name, or panics if error has occurred.
+func MustGetNamed[T any](ctx context.Context, sl ServiceLocator, name string) T {
+	s, err := GetNamed[T](ctx, sl, name)
+	if err != nil {
+		panic(err)
+	}
+
+	return s
+}
+
+// Fills public fields of struct target points to with services registered in ServiceLocator,
+// following the same rules and tinysl tags as P.
+// Fields are left unchanged if error has occurred or optional dependency is not registered.
+func Inject(ctx context.Context, sl ServiceLocator, target any) error {
+	v := reflect.ValueOf(target)
+	if v.Kind() != reflect.Pointer || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return newInjectError(ErrInjectWrongTarget, reflect.TypeOf(target))
+	}
+
+	fields, err := publicFields(v.Elem().Type())
+	if err != nil {
+		return newInjectError(err, v.Type())
+	}
+
+	services := make([]any, len(fields.dependencies))
+	for i, dependency := range fields.dependencies {
+		services[i], err = getDependency(ctx, sl, dependency, fields.names[i], fields.optional[i])
+		if err != nil {
+			return newInjectError(err, v.Type())
+		}
+	}
+
+	for i, service := range services {
+		if service != nil {
+			v.Elem().FieldByIndex(fields.indexes[i]).Set(reflect.ValueOf(service))
+		}
+	}
+
+	return nil
+}
+
+// Calls fn with its parameters resolved from ServiceLocator and returns its results.
+// Same as constructor, fn can take context.Context as its first parameter and parameter objects embedding In.
+// If last result of fn is error, it is returned separately from other results.
+func Invoke(ctx context.Context, sl ServiceLocator, fn any) ([]any, error) {
+	f := reflect.ValueOf(fn)
+	if f.Kind() != reflect.Func || f.IsNil() {
+		return nil, newInvokeError(ErrInvokeNotAFunction, reflect.TypeOf(fn))
+	}
+
+	t := f.Type()
+	args := make([]reflect.Value, t.NumIn())
+	for i := range args {
+		argT := t.In(i)
+
+		switch {
+		case i == 0 && argT == contextInterface:
+			args[i] = reflect.ValueOf(&ctx).Elem()
+		case isParameterObject(argT):
+			fields, err := publicFields(argT)
+			if err != nil {
+				return nil, newInvokeError(err, t)
+			}
+
+			args[i] = reflect.New(argT).Elem()
+			for j, dependency := range fields.dependencies {
+				service, err := getDependency(ctx, sl, dependency, fields.names[j], fields.optional[j])
+				if err != nil {
+					return nil, newInvokeError(err, t)
+				}
+
+				if service != nil {
+					args[i].FieldByIndex(fields.indexes[j]).Set(reflect.ValueOf(service))
+				}
+			}
+		default:
+			service, err := getDependency(ctx, sl, argT, "", false)
+			if err != nil {
+				return nil, newInvokeError(err, t)
+			}
+
+			// service resolved as nil interface has no value to pass
+			args[i] = reflect.Zero(argT)
+			if service != nil {
+				args[i] = reflect.ValueOf(service)
+			}
+		}
+	}
+
+	var values []reflect.Value
+	if t.IsVariadic() {
+		values = f.CallSlice(args)
+	} else {
+		values = f.Call(args)
+	}
+
+	var err error
+	if n := len(values); n > 0 && t.Out(n-1) == errorInterface {
+		err, _ = values[n-1].Interface().(error)
+		values = values[:n-1]
+	}
+
+	results := make([]any, len(values))
+	for i, value := range values {
+		results[i] = value.Interface()
+	}
+
+	return results, err
+}
+
+// Returns service resolved same as constructor dependency,
+// or nil if optional dependency is not registered.
+func getDependency(ctx context.Context, sl ServiceLocator, serviceType reflect.Type, name string, optional bool) (any, error) {
+	service, err := sl.GetNamed(ctx, serviceType, name)
+
+	var notFound *ConstructorNotFoundError
+	if optional && errors.As(err, &notFound) && notFound.ServiceType == serviceType && notFound.Name == name {
+		return nil, nil
+	}
+
+	return service, err
+}
+
 // Your HTTP middleware function decorator.
 // Registers an error if no constructor was found with ServiceLocator
 // which should be checked with ServiceLocator.Err().
 func DecorateMiddleware[T any](sl ServiceLocator, fn func(T) func(http.Handler) http.Handler) func(http.Handler) http.Handler {
-	serviceType := reflect.TypeOf(new(T))
-	serviceName := serviceType.Elem().String()
+	serviceType := reflect.TypeOf(new(T)).Elem()
 
-	sl.EnsureAvailable(serviceName)
+	sl.EnsureAvailable(serviceType)
 
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			ctx := r.Context()
 
-			s, err := sl.Get(ctx, serviceName)
+			s, err := sl.Get(ctx, serviceType)
 			if err != nil {
 				panic(err)
 			}
@@ -157,15 +389,14 @@ func DecorateMiddleware[T any](sl ServiceLocator, fn func(T) func(http.Handler)
 // Registers an error if no constructor was found with ServiceLocator
 // which should be checked with ServiceLocator.Err().
 func DecorateHandler[T any, H http.Handler](sl ServiceLocator, fn func(T) H) http.HandlerFunc {
-	serviceType := reflect.TypeOf(new(T))
-	serviceName := serviceType.Elem().String()
+	serviceType := reflect.TypeOf(new(T)).Elem()
 
-	sl.EnsureAvailable(serviceName)
+	sl.EnsureAvailable(serviceType)
 
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
 
-		s, err := sl.Get(ctx, serviceName)
+		s, err := sl.Get(ctx, serviceType)
 		if err != nil {
 			panic(err)
 		}
@@ -174,21 +405,147 @@ func DecorateHandler[T any, H http.Handler](sl ServiceLocator, fn func(T) H) htt
 	})
 }
 
+// Optional dependency of type T.
+// Constructor taking Optional[T] is built even if T is not registered.
+type Optional[T any] struct {
+	service T
+	ok      bool
+}
+
+// Returns service and true if T is registered, or zero value and false otherwise.
+func (o Optional[T]) Get() (T, bool) {
+	return o.service, o.ok
+}
+
+// Returns service if T is registered, or fallback otherwise.
+func (o Optional[T]) OrElse(fallback T) T {
+	if !o.ok {
+		return fallback
+	}
+
+	return o.service
+}
+
+func (Optional[T]) serviceType() reflect.Type {
+	return reflect.TypeOf(new(T)).Elem()
+}
+
+func (Optional[T]) present(service any) any {
+	return Optional[T]{service: service.(T), ok: true}
+}
+
+type optionalDependency interface {
+	serviceType() reflect.Type
+	present(service any) any
+}
+
 // Lazy initialized service.
 // Will panic if error occurred during initialization.
+// Constructor taking Lazy[T] gets service of type T resolved when Lazy[T] is called,
+// so it can be used to break circular dependency, as long as constructor itself does not call it.
 type Lazy[T any] func(context.Context) T
 
+func (Lazy[T]) serviceType() reflect.Type {
+	return reflect.TypeOf(new(T)).Elem()
+}
+
+func (Lazy[T]) resolvedWith(get func(context.Context) (any, error)) any {
+	return Lazy[T](func(ctx context.Context) T {
+		s, err := get(ctx)
+		if err != nil {
+			panic(err)
+		}
+
+		return s.(T)
+	})
+}
+
+// Provider of service, same as Lazy[T], but returns error instead of panic.
+type Provider[T any] func(context.Context) (T, error)
+
+func (Provider[T]) serviceType() reflect.Type {
+	return reflect.TypeOf(new(T)).Elem()
+}
+
+func (Provider[T]) resolvedWith(get func(context.Context) (any, error)) any {
+	return Provider[T](func(ctx context.Context) (T, error) {
+		var nilValue T
+
+		s, err := get(ctx)
+		if err != nil {
+			return nilValue, err
+		}
+
+		return s.(T), nil
+	})
+}
+
+type lazyDependency interface {
+	serviceType() reflect.Type
+	resolvedWith(get func(context.Context) (any, error)) any
+}
+
+// Factory of service built with runtime arguments, registered with Container.AddFactory.
+// Constructor taking Factory[Args, T] gets function building new instance of T on each call,
+// with factory dependencies resolved using ctx.
+// Cleanup of instance is called together with PerContext cleanup of ctx.
+type Factory[Args, T any] func(ctx context.Context, args Args) (T, error)
+
+func (Factory[Args, T]) serviceType() reflect.Type {
+	return reflect.TypeOf(new(T)).Elem()
+}
+
+func (Factory[Args, T]) argsType() reflect.Type {
+	return reflect.TypeOf(new(Args)).Elem()
+}
+
+func (Factory[Args, T]) builtWith(build func(context.Context, reflect.Value) (any, error)) any {
+	return Factory[Args, T](func(ctx context.Context, args Args) (T, error) {
+		var nilValue T
+
+		s, err := build(ctx, reflect.ValueOf(&args).Elem())
+		if err != nil {
+			return nilValue, err
+		}
+
+		return s.(T), nil
+	})
+}
+
+type factoryDependency interface {
+	serviceType() reflect.Type
+	argsType() reflect.Type
+	builtWith(build func(context.Context, reflect.Value) (any, error)) any
+}
+
 // Returns lazy initialization of service registered in ServiceLocator.
 // Registers an error if no constructor was found with ServiceLocator
 // which should be checked with ServiceLocator.Err().
 func Prepare[T any](sl ServiceLocator) Lazy[T] {
-	serviceType := reflect.TypeOf(new(T))
-	serviceName := serviceType.Elem().String()
+	serviceType := reflect.TypeOf(new(T)).Elem()
+
+	sl.EnsureAvailable(serviceType)
+
+	return func(ctx context.Context) T {
+		s, err := sl.Get(ctx, serviceType)
+		if err != nil {
+			panic(err)
+		}
+
+		return s.(T)
+	}
+}
+
+// Returns lazy initialization of service registered in ServiceLocator with name.
+// Registers an error if no constructor was found with ServiceLocator
+// which should be checked with ServiceLocator.Err().
+func PrepareNamed[T any](sl ServiceLocator, name string) Lazy[T] {
+	serviceType := reflect.TypeOf(new(T)).Elem()
 
-	sl.EnsureAvailable(serviceName)
+	sl.EnsureAvailableNamed(serviceType, name)
 
 	return func(ctx context.Context) T {
-		s, err := sl.Get(ctx, serviceName)
+		s, err := sl.GetNamed(ctx, serviceType, name)
 		if err != nil {
 			panic(err)
 		}
@@ -2,9 +2,11 @@ package tinysl_test
 
 import (
 	"context"
+	"errors"
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -89,6 +91,37 @@ var _ = Describe("Functions", func() {
 			Expect(func() { lazy(ctx) }).To(Panic())
 		})
 	})
+
+	Context("PrepareNamed", func() {
+		It("should work", func() {
+			sl, err := tinysl.
+				Add(tinysl.Singleton, nameServiceConstructor).
+				Add(tinysl.Singleton, func() NameService { return NameProvider("Sam") }, tinysl.WithName("replica")).
+				ServiceLocator()
+
+			Expect(err).ShouldNot(HaveOccurred())
+
+			lazy := tinysl.PrepareNamed[NameService](sl, "replica")
+			service := lazy(context.TODO())
+
+			Expect(sl.Err()).ShouldNot(HaveOccurred())
+			Expect(service.Name()).To(Equal("Sam"))
+		})
+
+		It("should report error", func() {
+			sl, err := tinysl.
+				Add(tinysl.Singleton, nameServiceConstructor).
+				ServiceLocator()
+
+			Expect(err).ShouldNot(HaveOccurred())
+
+			lazy := tinysl.PrepareNamed[NameService](sl, "replica")
+
+			Expect(sl.Err()).Should(HaveOccurred())
+			Expect(sl.Err()).To(BeAssignableToTypeOf(&tinysl.ConstructorNotFoundError{}))
+			Expect(func() { lazy(context.TODO()) }).To(Panic())
+		})
+	})
 	Context("Decorate", func() {
 		It("should work", func() {
 			sl, err := tinysl.
@@ -169,4 +202,359 @@ var _ = Describe("Functions", func() {
 			Expect(sl.Err()).To(BeAssignableToTypeOf(&tinysl.ConstructorNotFoundError{}))
 		})
 	})
+
+	Context("Inject", func() {
+		It("should fill tagged fields of existing struct", func() {
+			sl, err := tinysl.
+				Add(tinysl.Singleton, nameServiceConstructor, tinysl.WithName("hero")).
+				Add(tinysl.Singleton, func() Announcer { return &Hero{"Sam"} }, tinysl.WithName("heroes"), tinysl.AsGroupMember).
+				ServiceLocator()
+
+			Expect(err).ShouldNot(HaveOccurred())
+
+			ctx, cancel := context.WithCancel(context.TODO())
+
+			defer cancel()
+
+			service := &ServiceWithTags{Name: "Sam"}
+			err = tinysl.Inject(ctx, sl, service)
+
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(service.Hero).To(BeNil())
+			Expect(service.NameService.Name()).To(Equal("Bob"))
+			Expect(service.Announcers).To(HaveLen(1))
+			Expect(service.Name).To(Equal("Sam"))
+		})
+
+		It("should leave fields unchanged on error", func() {
+			sl, err := tinysl.
+				Add(tinysl.Singleton, nameServiceConstructor, tinysl.WithName("hero")).
+				ServiceLocator()
+
+			Expect(err).ShouldNot(HaveOccurred())
+
+			ctx, cancel := context.WithCancel(context.TODO())
+
+			defer cancel()
+
+			service := &ServiceWithTags{}
+			err = tinysl.Inject(ctx, sl, service)
+
+			Expect(err).Should(HaveOccurred())
+			Expect(err).To(BeAssignableToTypeOf(&tinysl.InjectError{}))
+			Expect(errors.Unwrap(err)).To(BeAssignableToTypeOf(&tinysl.ConstructorNotFoundError{}))
+			Expect(service.NameService).To(BeNil())
+		})
+
+		It("should return error if target is not a pointer to a struct", func() {
+			sl, err := tinysl.
+				Add(tinysl.Singleton, nameServiceConstructor).
+				ServiceLocator()
+
+			Expect(err).ShouldNot(HaveOccurred())
+
+			ctx, cancel := context.WithCancel(context.TODO())
+
+			defer cancel()
+
+			Expect(tinysl.Inject(ctx, sl, ServiceWithPublicFields{})).To(MatchError(tinysl.ErrInjectWrongTarget))
+			Expect(tinysl.Inject(ctx, sl, (*ServiceWithPublicFields)(nil))).To(MatchError(tinysl.ErrInjectWrongTarget))
+			Expect(tinysl.Inject(ctx, sl, nil)).To(MatchError(tinysl.ErrInjectWrongTarget))
+		})
+	})
+
+	Context("Invoke", func() {
+		It("should call function with resolved parameters", func() {
+			sl, err := tinysl.
+				Add(tinysl.PerContext, nameServiceConstructor).
+				Add(tinysl.Transient, heroConstructor).
+				ServiceLocator()
+
+			Expect(err).ShouldNot(HaveOccurred())
+
+			ctx, cancel := context.WithCancel(context.TODO())
+
+			defer cancel()
+
+			results, err := tinysl.Invoke(ctx, sl, func(c context.Context, n NameService, h *Hero, o tinysl.Optional[*Impostor]) (context.Context, string, *Hero, bool) {
+				_, ok := o.Get()
+
+				return c, n.Name(), h, ok
+			})
+
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(results).To(HaveLen(4))
+			Expect(results[0]).To(Equal(ctx))
+			Expect(results[1]).To(Equal("Bob"))
+			Expect(results[3]).To(BeFalse())
+
+			hero, err := tinysl.Get[*Hero](ctx, sl)
+
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(results[2]).NotTo(BeIdenticalTo(hero))
+		})
+
+		It("should pass service resolved as nil interface", func() {
+			sl, err := tinysl.
+				Add(tinysl.Singleton, func() NameService { return nil }).
+				ServiceLocator()
+
+			Expect(err).ShouldNot(HaveOccurred())
+
+			results, err := tinysl.Invoke(context.TODO(), sl, func(n NameService) bool { return n == nil })
+
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(results).To(Equal([]any{true}))
+		})
+
+		It("should resolve parameter objects, Lazy and Factory", func() {
+			sl, err := tinysl.
+				Add(tinysl.Singleton, nameServiceConstructor, tinysl.WithName("hero")).
+				Add(tinysl.Singleton, func() Announcer { return &Hero{"Sam"} }, tinysl.AsGroupMember).
+				AddFactory(func(args HeroArgs) *Hero { return &Hero{args.Title} }).
+				ServiceLocator()
+
+			Expect(err).ShouldNot(HaveOccurred())
+
+			ctx, cancel := context.WithCancel(context.TODO())
+
+			defer cancel()
+
+			results, err := tinysl.Invoke(ctx, sl, func(params HeroParams, l tinysl.Lazy[[]Announcer], f tinysl.Factory[HeroArgs, *Hero]) (string, error) {
+				hero, err := f(ctx, HeroArgs{Title: params.NameService.Name()})
+
+				return hero.Announce() + " " + l(ctx)[0].Announce(), err
+			})
+
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(results).To(Equal([]any{"Bob is our hero! Sam is our hero!"}))
+		})
+
+		It("should return error of function", func() {
+			sl, err := tinysl.
+				Add(tinysl.Singleton, nameServiceConstructor).
+				ServiceLocator()
+
+			Expect(err).ShouldNot(HaveOccurred())
+
+			ctx, cancel := context.WithCancel(context.TODO())
+
+			defer cancel()
+
+			results, err := tinysl.Invoke(ctx, sl, func(n NameService) (string, error) { return n.Name(), io.EOF })
+
+			Expect(err).To(MatchError(io.EOF))
+			Expect(results).To(Equal([]any{"Bob"}))
+		})
+
+		It("should return error if parameter is not registered", func() {
+			sl, err := tinysl.
+				Add(tinysl.Singleton, nameServiceConstructor).
+				ServiceLocator()
+
+			Expect(err).ShouldNot(HaveOccurred())
+
+			ctx, cancel := context.WithCancel(context.TODO())
+
+			defer cancel()
+
+			_, err = tinysl.Invoke(ctx, sl, func(*Hero) {})
+
+			Expect(err).To(BeAssignableToTypeOf(&tinysl.InvokeError{}))
+			Expect(errors.Unwrap(err)).To(BeAssignableToTypeOf(&tinysl.ConstructorNotFoundError{}))
+
+			_, err = tinysl.Invoke(ctx, sl, "not a function")
+
+			Expect(err).To(MatchError(tinysl.ErrInvokeNotAFunction))
+		})
+	})
+
+	Context("WithScopedValue", func() {
+		It("should resolve dependencies with values seeded into context", func() {
+			sl, err := tinysl.
+				Add(tinysl.PerContext, tinysl.Scoped[NameService]).
+				Add(tinysl.PerContext, heroConstructor).
+				ServiceLocator()
+
+			Expect(err).ShouldNot(HaveOccurred())
+
+			ctx, cancel := context.WithCancel(tinysl.WithScopedValue[NameService](context.TODO(), NameProvider("Sam")))
+
+			defer cancel()
+
+			hero, err := tinysl.Get[*Hero](ctx, sl)
+
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(hero.Announce()).To(Equal("Sam is our hero!"))
+
+			notSeededCtx, cancelNotSeeded := context.WithCancel(context.TODO())
+
+			defer cancelNotSeeded()
+
+			_, err = tinysl.Get[*Hero](notSeededCtx, sl)
+
+			Expect(err).Should(HaveOccurred())
+
+			notSeeded := new(tinysl.ScopedValueNotSeededError)
+
+			Expect(errors.As(err, &notSeeded)).To(BeTrue())
+			Expect(notSeeded.ServiceType).To(Equal(reflect.TypeOf(new(NameService)).Elem()))
+		})
+
+		It("should return an error if scoped value is not declared", func() {
+			_, err := tinysl.
+				Add(tinysl.PerContext, heroConstructor).
+				ServiceLocator()
+
+			Expect(err).Should(HaveOccurred())
+			Expect(errors.Unwrap(err)).Should(BeAssignableToTypeOf(new(tinysl.ConstructorNotFoundError)))
+
+			_, err = tinysl.
+				Add(tinysl.PerContext, tinysl.Scoped[NameService]).
+				Add(tinysl.Singleton, heroConstructor).
+				ServiceLocator()
+
+			Expect(err).Should(HaveOccurred())
+			Expect(errors.Unwrap(err)).Should(BeAssignableToTypeOf(new(tinysl.ScopeHierarchyError)))
+		})
+	})
+
+	Context("Override", func() {
+		It("should resolve overridden services only in context and contexts derived from it", func() {
+			sl, err := tinysl.
+				Add(tinysl.PerContext, nameServiceConstructor).
+				Add(tinysl.PerContext, heroConstructor).
+				Add(tinysl.Transient, impostorConstructor).
+				ServiceLocator()
+
+			Expect(err).ShouldNot(HaveOccurred())
+
+			ctx, cancel := context.WithCancel(context.TODO())
+
+			defer cancel()
+
+			overridden, cancelOverridden := context.WithCancel(tinysl.Override[NameService](ctx, NameProvider("Sam")))
+
+			defer cancelOverridden()
+
+			hero, err := tinysl.Get[*Hero](overridden, sl)
+
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(hero.Announce()).To(Equal("Sam is our hero!"))
+
+			hero, err = tinysl.Get[*Hero](ctx, sl)
+
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(hero.Announce()).To(Equal("Bob is our hero!"))
+
+			overridden = tinysl.OverrideFunc(overridden, func(ctx context.Context) (*Impostor, error) {
+				return &Impostor{name: "Tom"}, nil
+			})
+
+			impostor, err := tinysl.Get[*Impostor](overridden, sl)
+
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(impostor.Name()).To(Equal("Tom"))
+			Expect(tinysl.MustGet[NameService](overridden, sl).Name()).To(Equal("Sam"))
+		})
+
+		It("should share overridden PerContext service within context", func() {
+			calls := 0
+			sl, err := tinysl.
+				Add(tinysl.PerContext, nameServiceConstructor).
+				Add(tinysl.PerContext, heroConstructor, tinysl.WithName("hero")).
+				ServiceLocator()
+
+			Expect(err).ShouldNot(HaveOccurred())
+
+			ctx, cancel := context.WithCancel(context.TODO())
+
+			defer cancel()
+
+			ctx = tinysl.OverrideNamedFunc(ctx, "hero", func(ctx context.Context) (*Hero, error) {
+				calls++
+				return &Hero{"Sam"}, nil
+			})
+
+			hero, err := tinysl.GetNamed[*Hero](ctx, sl, "hero")
+
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(tinysl.MustGetNamed[*Hero](ctx, sl, "hero")).To(BeIdenticalTo(hero))
+			Expect(calls).To(Equal(1))
+		})
+
+		It("should resolve overridden interface service is bound to with As", func() {
+			sl, err := tinysl.
+				Add(tinysl.PerContext, func() *Impostor { return &Impostor{name: "Bob"} }, tinysl.As[NameService]()).
+				Add(tinysl.PerContext, heroConstructor).
+				ServiceLocator()
+
+			Expect(err).ShouldNot(HaveOccurred())
+
+			ctx, cancel := context.WithCancel(context.TODO())
+
+			defer cancel()
+
+			overridden := tinysl.Override[NameService](ctx, NameProvider("Sam"))
+
+			Expect(tinysl.MustGet[NameService](overridden, sl).Name()).To(Equal("Sam"))
+			Expect(tinysl.MustGet[*Hero](overridden, sl).Announce()).To(Equal("Sam is our hero!"))
+			Expect(tinysl.MustGet[*Impostor](overridden, sl).Name()).To(Equal("Bob"))
+			Expect(tinysl.MustGet[NameService](ctx, sl).Name()).To(Equal("Bob"))
+		})
+
+		It("should return an error if Singleton is overridden", func() {
+			sl, err := tinysl.
+				Add(tinysl.Singleton, nameServiceConstructor).
+				ServiceLocator()
+
+			Expect(err).ShouldNot(HaveOccurred())
+
+			ctx := tinysl.Override[NameService](context.TODO(), NameProvider("Sam"))
+
+			_, err = tinysl.Get[NameService](ctx, sl)
+
+			Expect(err).Should(HaveOccurred())
+			Expect(errors.Unwrap(err)).To(MatchError(tinysl.ErrOverrideSingleton))
+		})
+
+		It("should build Singletons without overrides", func() {
+			sl, err := tinysl.
+				New(tinysl.SilenceUseSingletonWarnings).
+				Add(tinysl.PerContext, nameServiceConstructor).
+				Add(tinysl.Singleton, heroConstructor).
+				ServiceLocator()
+
+			Expect(err).ShouldNot(HaveOccurred())
+
+			ctx, cancel := context.WithCancel(context.TODO())
+
+			defer cancel()
+
+			overridden := tinysl.Override[NameService](ctx, NameProvider("Sam"))
+			hero, err := tinysl.Get[*Hero](overridden, sl)
+
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(hero.Announce()).To(Equal("Bob is our hero!"))
+			Expect(tinysl.MustGet[*Hero](ctx, sl)).To(BeIdenticalTo(hero))
+			Expect(tinysl.MustGet[NameService](overridden, sl).Name()).To(Equal("Sam"))
+		})
+
+		It("should return an error of override constructor", func() {
+			sl, err := tinysl.
+				Add(tinysl.Transient, nameServiceConstructor).
+				ServiceLocator()
+
+			Expect(err).ShouldNot(HaveOccurred())
+
+			ctx := tinysl.OverrideFunc(context.TODO(), func(ctx context.Context) (NameService, error) {
+				return nil, io.EOF
+			})
+
+			_, err = tinysl.Get[NameService](ctx, sl)
+
+			Expect(err).Should(HaveOccurred())
+			Expect(errors.Is(err, io.EOF)).To(BeTrue())
+		})
+	})
 })
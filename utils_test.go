@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"io"
 	"time"
+
+	"github.com/andriiyaremenko/tinysl"
 )
 
 type HelloService interface {
@@ -28,10 +30,110 @@ func (s ServiceWithPublicFields) Name() string {
 	return s.Dependency.Name()
 }
 
+type ServiceWithOptionalFields struct {
+	Dependency NameService `tinysl:"optional"`
+	Hero       *Hero       `tinysl:"optional"`
+}
+
+type embeddedDependencies struct {
+	Hero *Hero `tinysl:"optional"`
+}
+
+type ServiceWithTags struct {
+	embeddedDependencies
+
+	NameService NameService   `tinysl:"name=hero"`
+	Announcers  []Announcer   `tinysl:"group=heroes"`
+	Timeout     time.Duration `tinysl:"-"`
+	Name        string        `tinysl:"-"`
+}
+
+type ServiceWithInvalidTag struct {
+	NameService NameService `tinysl:"named=hero"`
+}
+
+func (s *ServiceWithInvalidTag) Name() string {
+	return s.NameService.Name()
+}
+
+type HeroParams struct {
+	tinysl.In
+
+	NameService NameService `tinysl:"name=hero"`
+	Announcers  []Announcer `tinysl:"group"`
+	Timer       *TableTimer `tinysl:"optional"`
+	Impostor    tinysl.Optional[*Impostor]
+}
+
+func heroWithParamsConstructor(_ context.Context, params HeroParams) *Hero {
+	name := params.NameService.Name()
+	for _, announcer := range params.Announcers {
+		name += ", " + announcer.Announce()
+	}
+
+	return &Hero{name}
+}
+
+type HeroesResult struct {
+	tinysl.Out
+
+	NameService NameService
+	Hero        *Hero     `tinysl:"name=hero"`
+	Member      Announcer `tinysl:"group"`
+}
+
+func heroesResultConstructor(cleanup func()) func() (HeroesResult, func(), error) {
+	return func() (HeroesResult, func(), error) {
+		hero := &Hero{"Bob"}
+
+		return HeroesResult{NameService: NameProvider("Bob"), Hero: hero, Member: hero}, cleanup, nil
+	}
+}
+
+type HeroArgs struct {
+	Title string
+}
+
+type HeroProviders struct {
+	name string
+}
+
+func (p HeroProviders) NameService() NameService {
+	return NameProvider(p.name)
+}
+
+func (p HeroProviders) PerContextHero(ctx context.Context, nameService NameService) (*Hero, error) {
+	return &Hero{nameService.Name()}, nil
+}
+
+func (p HeroProviders) TransientImpostor(nameService NameService) *Impostor {
+	return &Impostor{name: nameService.Name()}
+}
+
+type AnnotatedHeroProviders struct{}
+
+func (AnnotatedHeroProviders) Lifetimes() map[string]tinysl.Lifetime {
+	return map[string]tinysl.Lifetime{"Hero": tinysl.PerContext}
+}
+
+func (AnnotatedHeroProviders) Hero(ctx context.Context) *Hero {
+	return &Hero{"Bob"}
+}
+
+type lifetimesOf map[string]tinysl.Lifetime
+
+func (l lifetimesOf) Lifetimes() map[string]tinysl.Lifetime {
+	return l
+}
+
 type NameService interface {
 	Name() string
 }
 
+type Announcer interface {
+	Announce() string
+}
+
 type NameProvider string
 
 func (s NameProvider) Name() string {
@@ -145,6 +247,12 @@ func heroConstructorWithCleanup(cleanup func()) func(nameService NameService) (*
 	}
 }
 
+func heroFactoryWithCleanup(cleanup func()) func(NameService, HeroArgs) (*Hero, func(), error) {
+	return func(nameService NameService, args HeroArgs) (*Hero, func(), error) {
+		return &Hero{args.Title + " " + nameService.Name()}, cleanup, nil
+	}
+}
+
 func scaredHeroConstructorWithCleanup(nameService NameService) (*Hero, error) {
 	panic(fmt.Errorf("scared"))
 }